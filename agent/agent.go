@@ -0,0 +1,179 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// the requests understood by the agent, one per connection. The requests and the responses are JSON strings,
+// a passphrase can have any character
+const (
+	GET  = "get"
+	LOCK = "lock"
+)
+
+// DEFAULT_TIMEOUT is how long the agent keeps the passphrase
+const DEFAULT_TIMEOUT = 15 * time.Minute
+
+// Socket returns the socket of the agent: $XDG_RUNTIME_DIR/snip/agent.sock, snip-<uid>/agent.sock in the temp directory otherwise
+func Socket() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+
+	if dir == "" {
+		return filepath.Join(os.TempDir(), fmt.Sprintf("snip-%d", os.Getuid()), "agent.sock")
+	}
+
+	return filepath.Join(dir, "snip", "agent.sock")
+}
+
+// Serve keeps the passphrase in memory and gives it to the processes of the user connecting to the socket,
+// until the timeout is over or the agent is locked
+func Serve(socket, passphrase string, timeout time.Duration) error {
+	dir := filepath.Dir(socket)
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	// only the user can connect to the socket
+	if err := os.Chmod(dir, 0700); err != nil {
+		return err
+	}
+
+	if err := checkDir(dir); err != nil {
+		return err
+	}
+
+	// a previous agent which did not stop cleanly leaves its socket
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	listener, err := net.Listen("unix", socket)
+
+	if err != nil {
+		return err
+	}
+
+	defer listener.Close()
+
+	timer := time.AfterFunc(timeout, func() { listener.Close() })
+	defer timer.Stop()
+
+	for {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			// closed by the timer
+			return nil
+		}
+
+		if locked := handle(conn, passphrase); locked {
+			return nil
+		}
+	}
+}
+
+// handle answers the request of the connection and tells if the agent is locked
+func handle(conn net.Conn, passphrase string) bool {
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	var request string
+
+	if err := json.NewDecoder(conn).Decode(&request); err != nil {
+		return false
+	}
+
+	switch request {
+	case GET:
+		json.NewEncoder(conn).Encode(passphrase)
+	case LOCK:
+		return true
+	}
+
+	return false
+}
+
+// Get returns the passphrase kept by the agent, false when no agent is running
+func Get(socket string) (string, bool) {
+	response, ok := request(socket, GET)
+
+	if !ok || response == "" {
+		return "", false
+	}
+
+	return response, true
+}
+
+// Lock stops the agent, it tells if an agent was running
+func Lock(socket string) bool {
+	_, ok := request(socket, LOCK)
+	return ok
+}
+
+// request sends the request to the agent of the socket, only when the socket and its directory belong to the user
+func request(socket, request string) (string, bool) {
+	if checkSocket(socket) != nil {
+		return "", false
+	}
+
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+
+	if err != nil {
+		return "", false
+	}
+
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return "", false
+	}
+
+	// the agent does not answer a lock
+	var response string
+	json.NewDecoder(conn).Decode(&response)
+
+	return response, true
+}
+
+// checkSocket refuses the socket unless the socket and its directory belong to the user and the directory is 0700,
+// e.g. a directory created by another user in the temp directory
+func checkSocket(socket string) error {
+	if err := checkDir(filepath.Dir(socket)); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(socket)
+
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 || !owned(info) {
+		return UnsafeSocketError{Path: socket}
+	}
+
+	return nil
+}
+
+func checkDir(dir string) error {
+	info, err := os.Lstat(dir)
+
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() || info.Mode().Perm() != 0700 || !owned(info) {
+		return UnsafeSocketError{Path: dir}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package agent_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/baopham/snip/agent"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Agent", func() {
+	var dir, socket string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-agent")
+		Expect(err).To(BeNil())
+
+		socket = filepath.Join(dir, "run", "agent.sock")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	serve := func(timeout time.Duration) chan error {
+		done := make(chan error, 1)
+
+		go func() {
+			done <- Serve(socket, "pass\nphrase", timeout)
+		}()
+
+		Eventually(func() bool {
+			_, ok := Get(socket)
+			return ok
+		}).Should(BeTrue())
+
+		return done
+	}
+
+	It("should not find an agent which is not running", func() {
+		_, ok := Get(socket)
+
+		Expect(ok).To(BeFalse())
+		Expect(Lock(socket)).To(BeFalse())
+	})
+
+	It("should keep the passphrase until it is locked", func() {
+		done := serve(time.Minute)
+
+		passphrase, ok := Get(socket)
+
+		Expect(ok).To(BeTrue())
+		Expect(passphrase).To(Equal("pass\nphrase"))
+
+		info, err := os.Stat(filepath.Dir(socket))
+		Expect(err).To(BeNil())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0700)))
+
+		Expect(Lock(socket)).To(BeTrue())
+		Eventually(done).Should(Receive(BeNil()))
+
+		_, ok = Get(socket)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should forget the passphrase after the timeout", func() {
+		done := serve(200 * time.Millisecond)
+
+		Eventually(done).Should(Receive(BeNil()))
+
+		_, ok := Get(socket)
+		Expect(ok).To(BeFalse())
+	})
+	It("should not use a socket which others can use", func() {
+		done := serve(time.Minute)
+
+		Expect(os.Chmod(filepath.Dir(socket), 0755)).To(BeNil())
+
+		_, ok := Get(socket)
+		Expect(ok).To(BeFalse())
+
+		Expect(os.Chmod(filepath.Dir(socket), 0700)).To(BeNil())
+		Expect(Lock(socket)).To(BeTrue())
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
@@ -0,0 +1,9 @@
+package agent
+
+type UnsafeSocketError struct {
+	Path string
+}
+
+func (e UnsafeSocketError) Error() string {
+	return "The agent socket " + e.Path + " does not belong to you or can be used by others"
+}
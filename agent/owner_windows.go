@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package agent
+
+import "os"
+
+// owned tells whether the file belongs to the user, the temp directory of windows is the user's
+func owned(info os.FileInfo) bool {
+	return true
+}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"fmt"
 	s "github.com/baopham/snip/snippet"
+	"github.com/baopham/snip/suggest"
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
 	"strings"
@@ -21,13 +23,30 @@ func Add(c *cli.Context) error {
 		return MissingInfoError{Message: "Please specify your snippet"}
 	}
 
+	if c.Bool("parameterize") {
+		parameterized, err := parameterize(content)
+
+		if err != nil {
+			return err
+		}
+
+		content = parameterized
+	}
+
 	snippet := s.Snippet{
 		Keyword:     keyword,
 		Content:     content,
 		Description: description,
+		Sensitive:   c.Bool("sensitive"),
+		Encrypted:   c.Bool("encrypt"),
+		Tags:        s.ParseTags(c.String("tags")),
 	}
 
-	filePath, err := s.SnippetFile()
+	if c.Bool("runbook") {
+		snippet.Type = s.TYPE_RUNBOOK
+	}
+
+	filePath, err := destination(c)
 
 	if err != nil {
 		return err
@@ -37,7 +56,74 @@ func Add(c *cli.Context) error {
 		return err
 	}
 
+	if snippet.Sensitive || snippet.Encrypted {
+		color.Green("Saved: " + mask(&snippet))
+		return nil
+	}
+
 	color.Green("Saved: " + snippet.Content)
 
 	return nil
 }
+
+// parameterize asks which of the values likely to vary become placeholders, and how to name them
+func parameterize(content string) (string, error) {
+	var accepted []*suggest.Parameter
+
+	parameters := suggest.Parameters(content)
+
+	for _, parameter := range parameters {
+		message := fmt.Sprintf("Replace %s (%s) with {%s}? [y]es, [n]o or [r]ename", parameter.Value, parameter.Kind, parameter.Name)
+		answer, err := promptChoice(message, "yes", "no", "rename")
+
+		if err != nil {
+			return "", err
+		}
+
+		if answer == "no" {
+			continue
+		}
+
+		for answer == "rename" {
+			name, err := promptValue("name", false)
+
+			if err != nil {
+				return "", err
+			}
+
+			if name = Trim(name); name == "" {
+				continue
+			}
+
+			if err := suggest.Rename(content, parameters, parameter, name); err != nil {
+				color.Red(err.Error())
+				continue
+			}
+
+			answer = "yes"
+		}
+
+		accepted = append(accepted, parameter)
+	}
+
+	return suggest.Parameterize(content, accepted), nil
+}
+
+// destination returns the file the snippet is saved to: the project file with --local, the global file otherwise
+func destination(c *cli.Context) (string, error) {
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	if c.Bool("local") && c.Bool("global") {
+		return "", MissingInfoError{Message: "Please specify either --local or --global"}
+	}
+
+	if c.Bool("local") {
+		return library.LocalFile()
+	}
+
+	return library.Global(), nil
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/baopham/snip/history"
+	s "github.com/baopham/snip/snippet"
+	"github.com/urfave/cli"
+)
+
+func Again(c *cli.Context) error {
+	filePath, err := history.HistoryFile()
+
+	if err != nil {
+		return err
+	}
+
+	entries, err := history.Load(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return MissingInfoError{Message: "No execution found in the history"}
+	}
+
+	number := len(entries)
+
+	if arg := strings.TrimSpace(c.Args().First()); arg != "" {
+		number, err = strconv.Atoi(arg)
+
+		if err != nil || number < 1 || number > len(entries) {
+			return NotFoundHistoryEntry{Number: arg}
+		}
+	}
+
+	entry := entries[number-1]
+
+	snippet, err := findEntrySnippet(c, entry)
+
+	if err != nil {
+		return err
+	}
+
+	mapper := make(map[string]string, len(entry.Placeholders))
+
+	for name, value := range entry.Placeholders {
+		if !entry.IsRedacted(name) {
+			mapper[name] = value
+		}
+	}
+
+	if err := withProfile(c, snippet, mapper); err != nil {
+		return err
+	}
+
+	// the redacted values are secrets, read again from the providers,
+	// or the values of a sensitive snippet, asked again
+	if err := withSecrets(c, snippet, mapper); err != nil {
+		return err
+	}
+
+	if err := promptMissing(snippet, mapper); err != nil {
+		return err
+	}
+
+	if snippet.Type == s.TYPE_RUNBOOK {
+		return executeRunbook(c, snippet, mapper)
+	}
+
+	return execute(c, snippet, mapper)
+}
+
+// findEntrySnippet finds the snippet of the execution in the snippets file it was executed from,
+// not in the library of the current directory. Its includes are looked up in that file first
+func findEntrySnippet(c *cli.Context, entry *history.Entry) (*s.Snippet, error) {
+	if entry.Source == "" {
+		return findSnippet(c, entry.Keyword)
+	}
+
+	snippet, err := s.SearchExact(entry.Keyword, entry.Source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if snippet == nil || snippet.Keyword != entry.Keyword {
+		return nil, NotFoundSnippet{Keyword: entry.Keyword + " in " + entry.Source}
+	}
+
+	snippet.Source = entry.Source
+
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snippet.Expand(s.NewLibrary(append([]string{entry.Source}, library.Files...)...))
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/baopham/snip/history"
+	s "github.com/baopham/snip/snippet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Again", func() {
+	var dir, team, other string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-again")
+		Expect(err).To(BeNil())
+
+		team = filepath.Join(dir, "team.csv")
+		other = filepath.Join(dir, "other.csv")
+
+		Expect(ioutil.WriteFile(team, []byte("deploy,make deploy ENV={env} {@flags}\nflags,--verbose\n"), s.FileMode)).To(BeNil())
+		Expect(ioutil.WriteFile(other, []byte("deploy,rm -rf {env}\nflags,--force\n"), s.FileMode)).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Context("when calling findEntrySnippet()", func() {
+		It("should find the snippet in the file it was executed from", func() {
+			c := newContext(map[string]string{"file": other})
+
+			snippet, err := findEntrySnippet(c, &history.Entry{Keyword: "deploy", Source: team})
+
+			Expect(err).To(BeNil())
+			Expect(snippet.Content).To(Equal("make deploy ENV={env} --verbose"))
+			Expect(snippet.Source).To(Equal(team))
+		})
+
+		It("should use the current library for the executions saved without their file", func() {
+			c := newContext(map[string]string{"file": other})
+
+			snippet, err := findEntrySnippet(c, &history.Entry{Keyword: "deploy"})
+
+			Expect(err).To(BeNil())
+			Expect(snippet.Content).To(Equal("rm -rf {env}"))
+		})
+
+		It("should not take a snippet matching by its content for the keyword", func() {
+			c := newContext(map[string]string{"file": other})
+
+			_, err := findEntrySnippet(c, &history.Entry{Keyword: "--verbose", Source: team})
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
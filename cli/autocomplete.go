@@ -1,35 +1,24 @@
 package cli
 
 import (
-	"encoding/csv"
 	"fmt"
-	"github.com/baopham/snip/snippet"
 	"github.com/urfave/cli"
-	"os"
 )
 
 func Autocomplete(c *cli.Context) {
-	filePath, err := snippet.SnippetFile()
+	library, err := openLibrary(c)
 
 	if err != nil {
 		return
 	}
 
-	file, err := os.Open(filePath)
+	snippets, err := library.GetAll()
 
 	if err != nil {
 		return
 	}
 
-	reader := csv.NewReader(file)
-
-	for {
-		row, err := reader.Read()
-
-		if err != nil {
-			return
-		}
-
-		fmt.Println(row[0])
+	for _, found := range snippets {
+		fmt.Println(found.Keyword)
 	}
 }
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"flag"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/urfave/cli"
+
+	"testing"
+)
+
+func TestCli(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cli Suite")
+}
+
+// newContext returns the context of a command run with the given flags and arguments
+func newContext(flags map[string]string, args ...string) *cli.Context {
+	set := flag.NewFlagSet("snip", flag.ContinueOnError)
+
+	for _, name := range []string{"lib", "file", "profile"} {
+		set.String(name, "", "")
+	}
+
+	for name, value := range flags {
+		if set.Lookup(name) == nil {
+			set.String(name, "", "")
+		}
+
+		Expect(set.Set(name, value)).To(BeNil())
+	}
+
+	Expect(set.Parse(args)).To(BeNil())
+
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
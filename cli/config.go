@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/baopham/go-cliutil/cliutil"
+	"github.com/baopham/snip/config"
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+// conf is the config loaded at startup, see Configure
+var conf = &config.Config{}
+
+var searchModes = map[string]s.SearchCode{
+	config.SEARCH_FUZZY:     s.SEARCH_FUZZY,
+	config.SEARCH_SUBSTRING: s.SEARCH_SUBSTRING,
+	config.SEARCH_EXACT:     s.SEARCH_EXACT,
+}
+
+// settingRecord is a setting in the output of snip config list
+type settingRecord struct {
+	Key string `json:"key" yaml:"key"`
+	// Value is the value in use, the default when not set
+	Value string `json:"value" yaml:"value"`
+	// Origin is where the value comes from: default, file or the environment variable
+	Origin string `json:"origin" yaml:"origin"`
+	Usage  string `json:"usage" yaml:"usage"`
+}
+
+func (r *settingRecord) header() []string {
+	return []string{"key", "value", "origin", "usage"}
+}
+
+func (r *settingRecord) values() []string {
+	return []string{r.Key, r.Value, r.Origin, r.Usage}
+}
+
+// Configure sets the config used by the commands
+func Configure(c *config.Config) {
+	conf = c
+}
+
+func ConfigList(c *cli.Context) error {
+	file, err := config.File()
+
+	if err != nil {
+		return err
+	}
+
+	saved, err := config.Read(file)
+
+	if err != nil {
+		return err
+	}
+
+	records := make([]item, len(config.Settings))
+	rows := make([][]string, len(config.Settings))
+
+	for i, setting := range config.Settings {
+		r := &settingRecord{Key: setting.Key, Value: conf.Value(setting.Key), Origin: "default", Usage: setting.Usage}
+
+		if os.Getenv(setting.Env) != "" {
+			r.Origin = "$" + setting.Env
+		} else if saved.IsSet(setting.Key) {
+			r.Origin = "file"
+		}
+
+		records[i] = r
+		rows[i] = r.values()
+	}
+
+	return render(c, (&settingRecord{}).header(), records, func() error {
+		columns := []tableColumn{
+			{Header: "Key", Fixed: true},
+			{Header: "Value"},
+			{Header: "Origin", Fixed: true},
+			{Header: "Usage", Wrap: true},
+		}
+
+		if err := writeTable(c, columns, rows); err != nil {
+			return err
+		}
+
+		fmt.Println("File: " + file)
+
+		return nil
+	})
+}
+
+func ConfigGet(c *cli.Context) error {
+	key := strings.TrimSpace(c.Args().First())
+
+	if key == "" {
+		return MissingInfoError{Message: "Please specify the setting, one of: " + strings.Join(config.Keys(), ", ")}
+	}
+
+	value, err := conf.Get(key)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+
+	return nil
+}
+
+// ConfigSet saves the setting to the config file, an empty value unsets it
+func ConfigSet(c *cli.Context) error {
+	key := strings.TrimSpace(c.Args().First())
+
+	if key == "" || len(c.Args()) < 2 {
+		return MissingInfoError{Message: "Please specify the setting and its value: snip config set search substring"}
+	}
+
+	return saveSetting(key, c.Args().Get(1))
+}
+
+// ConfigEdit opens the config file in the editor
+func ConfigEdit(c *cli.Context) error {
+	file, err := config.File()
+
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("sh", "-c", conf.Value(config.EDITOR)+` "$0"`, file)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	_, err = config.Load(file)
+
+	return err
+}
+
+// saveSetting changes the setting in the config file, the other settings are kept as they are
+func saveSetting(key, value string) error {
+	file, err := config.File()
+
+	if err != nil {
+		return err
+	}
+
+	saved, err := config.Read(file)
+
+	if err != nil {
+		return err
+	}
+
+	if err := saved.Set(key, value); err != nil {
+		return err
+	}
+
+	if err := saved.Save(file); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(value) == "" {
+		color.Green(fmt.Sprintf("Setting %s is unset", key))
+	} else {
+		color.Green(fmt.Sprintf("Setting %s is %s", key, strings.TrimSpace(value)))
+	}
+
+	if setting, err := config.Lookup(key); err == nil && os.Getenv(setting.Env) != "" {
+		color.Yellow(fmt.Sprintf("$%s overrides it", setting.Env))
+	}
+
+	return nil
+}
+
+// forced tells whether to skip the confirmations, with --force or confirm = "never" in the config
+func forced(c *cli.Context) bool {
+	return c.Bool("force") || conf.Value(config.CONFIRM) == config.CONFIRM_NEVER
+}
+
+// confirm asks the question, prefixed with the chosen profile, unless the confirmations are skipped
+func confirm(c *cli.Context, format string, a ...interface{}) bool {
+	return forced(c) || cliutil.Prompt(profileLabel(c)+format, a...)
+}
+
+// shell returns the shell executing the snippets
+func shell() string {
+	return conf.Value(config.SHELL)
+}
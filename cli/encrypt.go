@@ -0,0 +1,394 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/baopham/snip/agent"
+	"github.com/baopham/snip/config"
+	"github.com/baopham/snip/crypt"
+	"github.com/baopham/snip/secret"
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+// PASSPHRASE_ENV is the passphrase of the encrypted snippets and the vault, asked otherwise
+const PASSPHRASE_ENV = "SNIP_PASSPHRASE"
+
+// passphraseAttempts is how many times a wrong passphrase is asked again
+const passphraseAttempts = 3
+
+var (
+	// unlocked is the passphrase which decrypted the snippets or the vault during this run
+	unlocked string
+	// unlockTimeout overrides the timeout of the config for snip unlock --timeout
+	unlockTimeout time.Duration
+	// openedLibrary is the library opened by the command, its encrypted snippets check a new passphrase
+	openedLibrary *s.Library
+)
+
+// agentRequest is what the background agent needs to keep the passphrase
+type agentRequest struct {
+	Passphrase string        `json:"passphrase"`
+	Timeout    time.Duration `json:"timeout"`
+}
+
+// Encrypt encrypts the whole snippets file of the library, or the content of one snippet: snip encrypt aws-login
+func Encrypt(c *cli.Context) error {
+	keyword := strings.TrimSpace(c.Args().First())
+
+	if keyword != "" {
+		return encryptSnippet(c, keyword, true)
+	}
+
+	file, err := encryptedFile(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.EncryptFile(file); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("%s is encrypted", file))
+
+	return nil
+}
+
+// Decrypt saves the snippets file of the library, or the content of one snippet, decrypted: snip decrypt aws-login
+func Decrypt(c *cli.Context) error {
+	keyword := strings.TrimSpace(c.Args().First())
+
+	if keyword != "" {
+		return encryptSnippet(c, keyword, false)
+	}
+
+	file, err := encryptedFile(c)
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.DecryptFile(file); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("%s is decrypted", file))
+
+	return nil
+}
+
+func encryptSnippet(c *cli.Context, keyword string, encrypt bool) error {
+	snippet, err := findRawSnippet(c, keyword)
+
+	if err != nil {
+		return err
+	}
+
+	if snippet.Encrypted == encrypt {
+		if encrypt {
+			return s.AlreadyEncryptedError{Name: keyword}
+		}
+
+		return s.NotEncryptedError{Name: keyword}
+	}
+
+	snippet.Encrypted = encrypt
+
+	if err := snippet.Update(snippet.Source); err != nil {
+		return err
+	}
+
+	if encrypt {
+		color.Green(fmt.Sprintf("Snippet %s is encrypted", keyword))
+	} else {
+		color.Green(fmt.Sprintf("Snippet %s is decrypted", keyword))
+	}
+
+	return nil
+}
+
+// encryptedFile returns the snippets file chosen with --file, the file of the library otherwise
+func encryptedFile(c *cli.Context) (string, error) {
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	return library.Global(), nil
+}
+
+// Unlock asks the passphrase once and keeps it in the agent until the timeout: snip unlock --timeout 1h
+func Unlock(c *cli.Context) error {
+	unlockTimeout = c.Duration("timeout")
+
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return err
+	}
+
+	// reading the snippets asks the passphrase if any is encrypted
+	if _, err := library.GetAll(); err != nil {
+		return err
+	}
+
+	if unlocked == "" {
+		vault, err := openVault()
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := vault.Names(); err != nil {
+			return err
+		}
+	}
+
+	if unlocked == "" {
+		color.Yellow("Nothing is encrypted")
+		return nil
+	}
+
+	if agentTimeout() == 0 {
+		color.Yellow(fmt.Sprintf("The passphrase is not kept, %s is 0", config.UNLOCK_TIMEOUT))
+		return nil
+	}
+
+	// the timeout starts again when the passphrase came from the agent
+	if err := startAgent(unlocked); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("Unlocked for %s", agentTimeout()))
+
+	return nil
+}
+
+// Lock stops the agent keeping the passphrase
+func Lock(c *cli.Context) error {
+	if !agent.Lock(agent.Socket()) {
+		color.Yellow("Already locked")
+		return nil
+	}
+
+	color.Green("Locked")
+
+	return nil
+}
+
+// UnlockAgent keeps the passphrase read from stdin until the timeout, started in the background once unlocked
+func UnlockAgent(c *cli.Context) error {
+	request := agentRequest{}
+
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		return err
+	}
+
+	return agent.Serve(agent.Socket(), request.Passphrase, request.Timeout)
+}
+
+// Passphrase returns the passphrase of the encrypted snippets and the vault: $SNIP_PASSPHRASE, the passphrase
+// kept by the agent or asked, a new one is asked twice when nothing is encrypted yet.
+// Only snip unlock keeps it in the agent
+func Passphrase(check func(passphrase string) error) (string, error) {
+	if check == nil {
+		return newPassphrase()
+	}
+
+	if passphrase := os.Getenv(PASSPHRASE_ENV); passphrase != "" {
+		if err := check(passphrase); err != nil {
+			return "", err
+		}
+
+		unlocked = passphrase
+
+		return passphrase, nil
+	}
+
+	for _, passphrase := range knownPassphrases() {
+		if check(passphrase) == nil {
+			unlocked = passphrase
+			return passphrase, nil
+		}
+	}
+
+	for i := 0; ; i++ {
+		passphrase, err := promptValue("Passphrase", true)
+
+		if err != nil {
+			return "", err
+		}
+
+		err = check(passphrase)
+
+		if _, wrong := err.(crypt.WrongPassphraseError); wrong && i+1 < passphraseAttempts {
+			color.Red(err.Error())
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		unlocked = passphrase
+
+		return passphrase, nil
+	}
+}
+
+// newPassphrase returns the passphrase encrypting new data: the passphrase of the data already encrypted
+// if any, so that one passphrase opens everything
+func newPassphrase() (string, error) {
+	check, err := encryptedCheck()
+
+	if err != nil {
+		return "", err
+	}
+
+	if check != nil {
+		return Passphrase(check)
+	}
+
+	if passphrase := os.Getenv(PASSPHRASE_ENV); passphrase != "" {
+		return passphrase, nil
+	}
+
+	// a new passphrase never comes from the agent, another user could run it
+	if unlocked != "" {
+		return unlocked, nil
+	}
+
+	passphrase, err := promptValue("New passphrase", true)
+
+	if err != nil {
+		return "", err
+	}
+
+	if passphrase == "" {
+		return "", MissingInfoError{Message: "Please specify a passphrase"}
+	}
+
+	again, err := promptValue("Confirm the passphrase", true)
+
+	if err != nil {
+		return "", err
+	}
+
+	if again != passphrase {
+		return "", MissingInfoError{Message: "The passphrases do not match"}
+	}
+
+	unlocked = passphrase
+
+	return passphrase, nil
+}
+
+// encryptedCheck returns the check of a passphrase against the data already encrypted, the snippets of
+// the library in use, the library of the config when the command opens none, or the vault.
+// It is nil when nothing is encrypted yet
+func encryptedCheck() (func(passphrase string) error, error) {
+	var files []string
+
+	library := openedLibrary
+
+	if library == nil {
+		library, _ = s.OpenLibrary(conf.Value(config.LIBRARY))
+	}
+
+	if library != nil {
+		files = append(files, library.Files...)
+	}
+
+	if vault, err := secret.VaultFile(); err == nil {
+		files = append(files, vault)
+	}
+
+	data, err := s.EncryptedData(files...)
+
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	return func(passphrase string) error {
+		_, err := crypt.Decrypt(data, passphrase)
+		return err
+	}, nil
+}
+
+// knownPassphrases returns the passphrase unlocked during this run and the one kept by the agent
+func knownPassphrases() []string {
+	var known []string
+
+	if unlocked != "" {
+		known = append(known, unlocked)
+	}
+
+	if passphrase, ok := agent.Get(agent.Socket()); ok && passphrase != unlocked {
+		known = append(known, passphrase)
+	}
+
+	return known
+}
+
+// agentTimeout returns how long the agent keeps the passphrase, 0 when it is not kept
+func agentTimeout() time.Duration {
+	if unlockTimeout > 0 {
+		return unlockTimeout
+	}
+
+	timeout, err := time.ParseDuration(conf.Value(config.UNLOCK_TIMEOUT))
+
+	if err != nil {
+		return agent.DEFAULT_TIMEOUT
+	}
+
+	return timeout
+}
+
+// startAgent replaces the agent by a background process keeping the passphrase until the timeout
+func startAgent(passphrase string) error {
+	agent.Lock(agent.Socket())
+
+	return startUnlockAgent(agentRequest{Passphrase: passphrase, Timeout: agentTimeout()})
+}
+
+func startUnlockAgent(request agentRequest) error {
+	executable, err := os.Executable()
+
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(request)
+
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(executable, "unlock-agent")
+
+	stdin, err := cmd.StdinPipe()
+
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	if _, err = stdin.Write(payload); err != nil {
+		return err
+	}
+
+	// the agent keeps running in the background once snip exits
+	return stdin.Close()
+}
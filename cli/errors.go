@@ -1,5 +1,10 @@
 package cli
 
+import (
+	"fmt"
+	"strings"
+)
+
 type MissingInfoError struct {
 	Message string
 }
@@ -15,3 +20,94 @@ func (e NotFoundSnippet) Error() string {
 func (e MissingInfoError) Error() string {
 	return e.Message
 }
+
+type NotFoundHistoryEntry struct {
+	Number string
+}
+
+func (e NotFoundHistoryEntry) Error() string {
+	return "Could not find the execution #" + e.Number + " in the history"
+}
+
+type MatrixError struct {
+	Failed int
+	Total  int
+}
+
+func (e MatrixError) Error() string {
+	return fmt.Sprintf("%d of %d runs failed", e.Failed, e.Total)
+}
+
+type RunbookError struct {
+	Keyword string
+	Failed  int
+	Aborted bool
+}
+
+func (e RunbookError) Error() string {
+	if e.Aborted {
+		return fmt.Sprintf("Runbook '%s' is aborted", e.Keyword)
+	}
+
+	return fmt.Sprintf("Runbook '%s' is completed with %d failed steps", e.Keyword, e.Failed)
+}
+
+type PipelineError struct {
+	Stage   int
+	Keyword string
+	Err     error
+}
+
+func (e PipelineError) Error() string {
+	return fmt.Sprintf("Stage %d (%s) of the pipeline failed: %s", e.Stage, e.Keyword, e.Err.Error())
+}
+
+type UnsupportedShellError struct {
+	Shell string
+}
+
+func (e UnsupportedShellError) Error() string {
+	return "Unsupported shell " + e.Shell + ", use one of: bash, zsh, fish"
+}
+
+type SensitiveClipboardError struct {
+	Provider string
+	Err      error
+}
+
+func (e SensitiveClipboardError) Error() string {
+	return fmt.Sprintf("The %s clipboard cannot be cleared automatically, use --print or another clipboard: %s", e.Provider, e.Err.Error())
+}
+
+type UnknownFormatError struct {
+	Format  string
+	Formats []string
+}
+
+func (e UnknownFormatError) Error() string {
+	return "Unknown format " + e.Format + ", use one of: " + strings.Join(e.Formats, ", ")
+}
+
+type UnknownColumnError struct {
+	Column string
+}
+
+func (e UnknownColumnError) Error() string {
+	return "Unknown column " + e.Column + ", use some of: keyword, content, description, tags, type, source"
+}
+
+type UnknownConflictPolicyError struct {
+	Policy string
+}
+
+func (e UnknownConflictPolicyError) Error() string {
+	return "Unknown conflict policy " + e.Policy + ", use one of: " + strings.Join(conflictPolicies, ", ")
+}
+
+type NoMatchingSnippetError struct {
+	Command string
+}
+
+func (e NoMatchingSnippetError) Error() string {
+	return "No snippet generates: " + e.Command
+}
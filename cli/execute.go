@@ -2,49 +2,163 @@ package cli
 
 import (
 	"fmt"
-	"github.com/atotto/clipboard"
-	"github.com/baopham/go-cliutil/cliutil"
-	"github.com/fatih/color"
-	"github.com/urfave/cli"
 	"os"
 	"os/exec"
+	"time"
+
+	"github.com/baopham/snip/clipboard"
+	"github.com/baopham/snip/history"
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
 )
 
 func Execute(c *cli.Context) error {
-	content, err := getSnippetContent(c)
+	if isPipeline(c) {
+		return executePipeline(c)
+	}
+
+	snippet, mapper, err := getSnippet(c)
 
 	if err != nil {
 		return err
 	}
 
-	if !c.Bool("force") {
-		yes := cliutil.Prompt("Are you sure you want to execute: %s", content)
+	if snippet.Type == s.TYPE_RUNBOOK {
+		return executeRunbook(c, snippet, mapper)
+	}
 
-		if !yes {
-			return nil
-		}
+	// the values are only split on commas when asked to, they may hold literal commas
+	if !c.Bool("matrix") {
+		return execute(c, snippet, mapper)
 	}
 
-	cmd := exec.Command("bash", "-c", content)
+	values := getPlaceholderValues(c.Args())
 
-	if c.Bool("output") {
-		output, err := cmd.Output()
-		if err != nil {
-			return err
+	// the values of the profile for the placeholders which were not given
+	for k, v := range mapper {
+		if _, ok := values[k]; !ok {
+			values[k] = []string{v}
 		}
+	}
 
-		err = clipboard.WriteAll(string(output))
+	runs := s.ExpandMatrix(values)
 
-		if err != nil {
-			return err
-		}
+	if len(runs) > 1 {
+		return executeMatrix(c, snippet, values)
+	}
+
+	return execute(c, snippet, runs[0])
+}
+
+func execute(c *cli.Context, snippet *s.Snippet, mapper map[string]string) error {
+	if !confirm(c, "Are you sure you want to execute: %s", display(snippet, mapper)) {
+		return nil
+	}
+
+	cmd := exec.Command(shell(), "-c", snippet.Build(mapper))
 
-		color.Green(fmt.Sprintf("`%s` *output* has been saved to your clipboard", content))
-	} else {
+	if !c.Bool("output") {
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
-		return cmd.Run()
+		return run(cmd, snippet, mapper)
+	}
+
+	provider, err := getClipboard(c)
+
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+
+	err = record(snippet, mapper, func() error {
+		var err error
+		out, err = cmd.Output()
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = provider.Write(string(out))
+
+	if err != nil {
+		return err
+	}
+
+	if provider.Name() != clipboard.STDOUT {
+		color.Green(fmt.Sprintf("`%s` *output* has been saved to your clipboard (%s)", display(snippet, mapper), provider.Name()))
 	}
 
 	return nil
 }
+
+func run(cmd *exec.Cmd, snippet *s.Snippet, mapper map[string]string) error {
+	return record(snippet, mapper, cmd.Run)
+}
+
+// record runs fn and appends the execution to the history log
+func record(snippet *s.Snippet, mapper map[string]string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+
+	entry := history.Entry{
+		Time:         start,
+		Keyword:      snippet.Keyword,
+		Source:       snippet.Source,
+		Placeholders: redact(snippet, mapper),
+		Duration:     time.Since(start),
+		ExitCode:     exitCode(err),
+	}
+
+	entry.Command = snippet.Build(entry.Placeholders)
+
+	// the history log is not encrypted and is listed with history, the sensitive snippets stay hidden
+	if snippet.Encrypted || snippet.Sensitive {
+		entry.Command = history.Redacted
+
+		for name := range entry.Placeholders {
+			entry.Placeholders[name] = history.Redacted
+		}
+	}
+
+	entry.Dir, _ = os.Getwd()
+
+	if filePath, herr := history.HistoryFile(); herr == nil {
+		if herr = history.Append(entry, filePath); herr != nil {
+			color.Yellow("Could not save the execution to the history: " + herr.Error())
+		}
+	}
+
+	return err
+}
+
+func redact(snippet *s.Snippet, mapper map[string]string) map[string]string {
+	redacted := make(map[string]string, len(mapper))
+
+	for k, v := range mapper {
+		redacted[k] = v
+	}
+
+	for _, placeholder := range snippet.Placeholders() {
+		if _, ok := redacted[placeholder.Name]; ok && placeholder.Secret {
+			redacted[placeholder.Name] = history.Redacted
+		}
+	}
+
+	return redacted
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+
+	return -1
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/baopham/snip/history"
+	s "github.com/baopham/snip/snippet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Execute", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-cli")
+		Expect(err).To(BeNil())
+		Expect(os.Setenv(s.HOME_ENV, dir)).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(s.HOME_ENV)
+		os.RemoveAll(dir)
+	})
+
+	lastEntry := func() *history.Entry {
+		file, err := history.HistoryFile()
+		Expect(err).To(BeNil())
+
+		entries, err := history.Load(file)
+		Expect(err).To(BeNil())
+		Expect(entries).NotTo(BeEmpty())
+
+		return entries[len(entries)-1]
+	}
+
+	Context("when calling record()", func() {
+		It("should save the execution to the history", func() {
+			snippet := &s.Snippet{Keyword: "port", Content: "lsof -i :{p}"}
+
+			Expect(record(snippet, map[string]string{"p": "80"}, func() error { return nil })).To(BeNil())
+
+			entry := lastEntry()
+			Expect(entry.Keyword).To(Equal("port"))
+			Expect(entry.Command).To(Equal("lsof -i :80"))
+			Expect(entry.Placeholders).To(Equal(map[string]string{"p": "80"}))
+		})
+
+		It("should redact the command and the placeholders of a sensitive snippet", func() {
+			snippet := &s.Snippet{Keyword: "login", Content: "login -u {user} -p hunter2", Sensitive: true}
+
+			Expect(record(snippet, map[string]string{"user": "bao"}, func() error { return nil })).To(BeNil())
+
+			entry := lastEntry()
+			Expect(entry.Command).To(Equal(history.Redacted))
+			Expect(entry.Placeholders).To(Equal(map[string]string{"user": history.Redacted}))
+		})
+
+		It("should redact the steps of an encrypted runbook", func() {
+			runbook := &s.Snippet{Keyword: "deploy", Content: "# Login\naws sso login --profile {profile}", Type: s.TYPE_RUNBOOK, Encrypted: true}
+			step := runbook.Steps()[1].Snippet(runbook)
+
+			Expect(record(step, map[string]string{"profile": "prod"}, func() error { return nil })).To(BeNil())
+
+			Expect(lastEntry().Command).To(Equal(history.Redacted))
+		})
+	})
+
+	Context("when calling redact()", func() {
+		It("should hide the values of the secret placeholders only", func() {
+			snippet := &s.Snippet{Keyword: "login", Content: "login -u {user} -t {token:secret}"}
+			mapper := map[string]string{"user": "bao", "token": "s3cret"}
+
+			Expect(redact(snippet, mapper)).To(Equal(map[string]string{"user": "bao", "token": history.Redacted}))
+			Expect(mapper["token"]).To(Equal("s3cret"))
+		})
+	})
+})
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	htmlTemplate "html/template"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/baopham/snip/highlight"
+	s "github.com/baopham/snip/snippet"
+	"github.com/baopham/snip/util"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+var exportFormats = []string{FORMAT_JSON, FORMAT_YAML, FORMAT_CSV, FORMAT_TSV, FORMAT_MARKDOWN, FORMAT_HTML}
+
+// untagged is the group of the snippets without tags in the cheat sheets
+const untagged = "Untagged"
+
+// cheatSheet is the snippets grouped by tag, a snippet with several tags is in each of their groups
+type cheatSheet struct {
+	Title  string
+	Groups []*cheatSheetGroup
+}
+
+type cheatSheetGroup struct {
+	Name    string
+	Entries []*cheatSheetEntry
+}
+
+type cheatSheetEntry struct {
+	*snippetRecord
+	// Language of the code block
+	Language string
+	// Fence of the Markdown code block, longer than any backticks of the content
+	Fence string
+	// Usage is the command generating the snippet, e.g. snip x port p=<p>
+	Usage string
+}
+
+var markdownCheatSheet = template.Must(template.New("markdown").Parse(`# {{.Title}}
+{{range .Groups}}
+## {{.Name}}
+{{range .Entries}}
+### {{.Keyword}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+{{.Fence}}{{.Language}}
+{{.Content}}
+{{.Fence}}
+{{if .Placeholders}}
+Placeholders: {{range $i, $p := .Placeholders}}{{if $i}}, {{end}}` + "`{{$p.Name}}`" + `{{if $p.Secret}} (secret){{end}}{{end}}
+{{end}}
+Usage: ` + "`{{.Usage}}`" + `
+{{end}}{{end}}`))
+
+var htmlCheatSheet = htmlTemplate.Must(htmlTemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em; color: #24292e; }
+h2 { border-bottom: 1px solid #eaecef; padding-bottom: .3em; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; border-radius: 4px; }
+code { font-family: SFMono-Regular, Consolas, Menlo, monospace; }
+.placeholders, .usage { color: #586069; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<ul>
+{{range .Groups}}<li><a href="#{{.Name}}">{{.Name}}</a> ({{len .Entries}})</li>
+{{end}}</ul>
+{{range .Groups}}<h2 id="{{.Name}}">{{.Name}}</h2>
+{{range .Entries}}<h3>{{.Keyword}}</h3>
+{{if .Description}}<p>{{.Description}}</p>
+{{end}}<pre><code class="language-{{.Language}}">{{.Content}}</code></pre>
+{{if .Placeholders}}<p class="placeholders">Placeholders: {{range $i, $p := .Placeholders}}{{if $i}}, {{end}}<code>{{$p.Name}}</code>{{if $p.Secret}} (secret){{end}}{{end}}</p>
+{{end}}<p class="usage">Usage: <code>{{.Usage}}</code></p>
+{{end}}{{end}}</body>
+</html>
+`))
+
+// Export writes the library, or the snippets with --tag, as JSON, YAML or as a Markdown or HTML cheat sheet
+func Export(c *cli.Context) error {
+	format := strings.ToLower(strings.TrimSpace(c.String("format")))
+
+	if format == "md" {
+		format = FORMAT_MARKDOWN
+	}
+
+	// checked before the output file is created, a typo would truncate it
+	if !contains(exportFormats, format) {
+		return UnknownFormatError{Format: format, Formats: exportFormats}
+	}
+
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return err
+	}
+
+	snippets, err := library.GetAll()
+
+	if err != nil {
+		return err
+	}
+
+	tag := strings.TrimSpace(c.String("tag"))
+	exported := make([]*s.Snippet, 0)
+	encrypted := 0
+
+	for _, snippet := range visible(c, snippets) {
+		if tag != "" && !snippet.HasTag(tag) {
+			continue
+		}
+
+		// the export is not encrypted
+		if snippet.Encrypted && !c.Bool("decrypt") {
+			encrypted++
+			continue
+		}
+
+		exported = append(exported, snippet)
+	}
+
+	if encrypted > 0 {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "%d encrypted snippets are not exported, use --decrypt to export them in plaintext\n", encrypted)
+	}
+
+	var out io.Writer = os.Stdout
+
+	if output := strings.TrimSpace(c.String("output")); output != "" {
+		file, err := os.Create(output)
+
+		if err != nil {
+			return err
+		}
+
+		defer util.Check(file.Close)
+
+		out = file
+	}
+
+	switch format {
+	case FORMAT_MARKDOWN:
+		err = markdownCheatSheet.Execute(out, newCheatSheet(exported, tag, library))
+	case FORMAT_HTML:
+		err = htmlCheatSheet.Execute(out, newCheatSheet(exported, tag, library))
+	default:
+		err = encode(out, format, (&snippetRecord{}).header(), snippetRecords(exported))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if output := strings.TrimSpace(c.String("output")); output != "" {
+		color.Green("Exported %d snippets to %s", len(exported), output)
+	}
+
+	return nil
+}
+
+func newCheatSheet(snippets []*s.Snippet, tag string, library *s.Library) *cheatSheet {
+	sheet := &cheatSheet{Title: "Snippets"}
+	groups := make(map[string]*cheatSheetGroup)
+
+	add := func(name string, entry *cheatSheetEntry) {
+		if groups[name] == nil {
+			groups[name] = &cheatSheetGroup{Name: name}
+			sheet.Groups = append(sheet.Groups, groups[name])
+		}
+
+		groups[name].Entries = append(groups[name].Entries, entry)
+	}
+
+	for _, snippet := range snippets {
+		entry := newCheatSheetEntry(snippet, library)
+
+		switch {
+		case tag != "":
+			add(tag, entry)
+		case len(snippet.Tags) == 0:
+			add(untagged, entry)
+		default:
+			for _, t := range snippet.Tags {
+				add(t, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(sheet.Groups, func(i, j int) bool {
+		if sheet.Groups[i].Name == untagged || sheet.Groups[j].Name == untagged {
+			return sheet.Groups[j].Name == untagged && sheet.Groups[i].Name != untagged
+		}
+
+		return sheet.Groups[i].Name < sheet.Groups[j].Name
+	})
+
+	return sheet
+}
+
+// newCheatSheetEntry documents the snippet with the placeholders of its includes too
+func newCheatSheetEntry(snippet *s.Snippet, library *s.Library) *cheatSheetEntry {
+	expanded, err := snippet.Expand(library)
+
+	if err != nil {
+		expanded = snippet
+	}
+
+	r := newSnippetRecord(expanded)
+	r.Content = snippet.Content
+
+	usage := []string{"snip x " + snippet.Keyword}
+
+	// the secrets are read from the secret providers, not given on the command line
+	for _, placeholder := range r.Placeholders {
+		if !placeholder.Secret {
+			usage = append(usage, placeholder.Name+"=<"+placeholder.Name+">")
+		}
+	}
+
+	language := highlight.Detect(snippet.Content)
+
+	if language == highlight.SHELL {
+		language = "bash"
+	}
+
+	fence := "```"
+
+	for strings.Contains(snippet.Content, fence) {
+		fence += "`"
+	}
+
+	return &cheatSheetEntry{
+		snippetRecord: r,
+		Language:      language,
+		Fence:         fence,
+		Usage:         strings.Join(usage, " "),
+	}
+}
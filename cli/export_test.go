@@ -0,0 +1,19 @@
+package cli
+
+import (
+	s "github.com/baopham/snip/snippet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export", func() {
+	Context("when calling newCheatSheetEntry()", func() {
+		It("should not suggest the secrets on the command line", func() {
+			snippet := &s.Snippet{Keyword: "login", Content: "login -u {user} -t {token:secret}"}
+
+			entry := newCheatSheetEntry(snippet, s.NewLibrary())
+
+			Expect(entry.Usage).To(Equal("snip x login user=<user>"))
+		})
+	})
+})
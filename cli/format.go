@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/baopham/snip/config"
+	s "github.com/baopham/snip/snippet"
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	FORMAT_TABLE = "table"
+	FORMAT_JSON  = "json"
+	FORMAT_YAML  = "yaml"
+	FORMAT_CSV   = "csv"
+	FORMAT_TSV   = "tsv"
+
+	FORMAT_MARKDOWN = "markdown"
+	FORMAT_HTML     = "html"
+)
+
+var formats = []string{FORMAT_TABLE, FORMAT_JSON, FORMAT_YAML, FORMAT_CSV, FORMAT_TSV}
+
+// item is one element of the output of a read command
+type item interface {
+	// header returns the field names, the same as in JSON and YAML
+	header() []string
+	values() []string
+}
+
+type placeholderRecord struct {
+	Name   string `json:"name" yaml:"name"`
+	Secret bool   `json:"secret" yaml:"secret"`
+}
+
+// snippetRecord is the stable representation of a snippet in the machine readable formats
+type snippetRecord struct {
+	Keyword      string              `json:"keyword" yaml:"keyword"`
+	Description  string              `json:"description" yaml:"description"`
+	Content      string              `json:"content" yaml:"content"`
+	Type         string              `json:"type" yaml:"type"`
+	Sensitive    bool                `json:"sensitive" yaml:"sensitive"`
+	Tags         []string            `json:"tags" yaml:"tags"`
+	Placeholders []placeholderRecord `json:"placeholders" yaml:"placeholders"`
+	Source       string              `json:"source" yaml:"source"`
+	Encrypted    bool                `json:"encrypted" yaml:"encrypted"`
+	// Updated is RFC3339, empty for the snippets saved before it was recorded
+	Updated string `json:"updated,omitempty" yaml:"updated,omitempty"`
+}
+
+func newSnippetRecord(snippet *s.Snippet) *snippetRecord {
+	r := &snippetRecord{
+		Keyword:      snippet.Keyword,
+		Description:  snippet.Description,
+		Content:      snippet.Content,
+		Type:         string(snippet.Type),
+		Sensitive:    snippet.Sensitive,
+		Tags:         append(make([]string, 0), snippet.Tags...),
+		Placeholders: make([]placeholderRecord, 0),
+		Source:       sourceLabel(snippet.Source),
+		Encrypted:    snippet.Encrypted,
+	}
+
+	if !snippet.Updated.IsZero() {
+		r.Updated = snippet.Updated.Format(time.RFC3339)
+	}
+
+	if r.Type == "" {
+		r.Type = "command"
+	}
+
+	for _, placeholder := range snippet.Placeholders() {
+		r.Placeholders = append(r.Placeholders, placeholderRecord{Name: placeholder.Name, Secret: placeholder.Secret})
+	}
+
+	return r
+}
+
+func (r *snippetRecord) header() []string {
+	return []string{"keyword", "description", "content", "type", "sensitive", "tags", "placeholders", "source", "encrypted", "updated"}
+}
+
+func (r *snippetRecord) values() []string {
+	placeholders := make([]string, len(r.Placeholders))
+
+	for i, placeholder := range r.Placeholders {
+		placeholders[i] = placeholder.Name
+
+		if placeholder.Secret {
+			placeholders[i] += ":secret"
+		}
+	}
+
+	return []string{r.Keyword, r.Description, r.Content, r.Type, fmt.Sprint(r.Sensitive), strings.Join(r.Tags, ","), strings.Join(placeholders, " "), r.Source, fmt.Sprint(r.Encrypted), r.Updated}
+}
+
+func snippetRecords(snippets []*s.Snippet) []item {
+	records := make([]item, len(snippets))
+
+	for i, snippet := range snippets {
+		records[i] = newSnippetRecord(snippet)
+	}
+
+	return records
+}
+
+// render outputs the records in the format chosen with --format or with the --template,
+// table renders the default human readable output. The header is written even without records
+func render(c *cli.Context, header []string, records []item, table func() error) error {
+	if text := c.String("template"); text != "" {
+		return renderTemplate(text, records)
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.String("format")))
+
+	if !c.IsSet("format") {
+		format = conf.Value(config.FORMAT)
+	}
+
+	if format == "" || format == FORMAT_TABLE {
+		return table()
+	}
+
+	return encode(os.Stdout, format, header, records)
+}
+
+// encode writes the records in one of the machine readable formats,
+// CSV and TSV start with the header row even when there is no record
+func encode(w io.Writer, format string, header []string, records []item) error {
+	switch format {
+	case FORMAT_JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case FORMAT_YAML:
+		out, err := yaml.Marshal(records)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(out)
+
+		return err
+	case FORMAT_CSV:
+		cw := csv.NewWriter(w)
+
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+
+		for _, r := range records {
+			if err := cw.Write(r.values()); err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+
+		return cw.Error()
+	case FORMAT_TSV:
+		if err := writeTSV(w, header); err != nil {
+			return err
+		}
+
+		for _, r := range records {
+			if err := writeTSV(w, r.values()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return UnknownFormatError{Format: format, Formats: formats}
+}
+
+// tsvEscaper keeps one record per line and one field per column, TSV has no quoting
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
+
+// writeTSV writes the fields joined with tabs, the tabs and line breaks in them are escaped
+func writeTSV(w io.Writer, fields []string) error {
+	escaped := make([]string, len(fields))
+
+	for i, field := range fields {
+		escaped[i] = tsvEscaper.Replace(field)
+	}
+
+	_, err := io.WriteString(w, strings.Join(escaped, "\t")+"\n")
+
+	return err
+}
+
+// renderTemplate executes the Go template for each record, e.g. {{.keyword}}: {{.content}}.
+// The fields are the ones of the JSON format
+func renderTemplate(text string, records []item) error {
+	tmpl, err := template.New("record").Parse(text)
+
+	if err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		b, err := json.Marshal(r)
+
+		if err != nil {
+			return err
+		}
+
+		fields := make(map[string]interface{})
+
+		if err = json.Unmarshal(b, &fields); err != nil {
+			return err
+		}
+
+		if err = tmpl.Execute(os.Stdout, fields); err != nil {
+			return err
+		}
+
+		if !strings.HasSuffix(text, "\n") {
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+
+	s "github.com/baopham/snip/snippet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Format", func() {
+	Context("when calling encode()", func() {
+		header := (&secretRecord{}).header()
+
+		It("should write the CSV header without records", func() {
+			var out bytes.Buffer
+
+			Expect(encode(&out, FORMAT_CSV, header, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("name\n"))
+		})
+
+		It("should write the TSV header without records", func() {
+			var out bytes.Buffer
+
+			Expect(encode(&out, FORMAT_TSV, header, nil)).To(Succeed())
+			Expect(out.String()).To(Equal("name\n"))
+		})
+
+		It("should escape the tabs and line breaks of the TSV fields instead of quoting them", func() {
+			var out bytes.Buffer
+			snippet := &s.Snippet{Keyword: "say", Description: `"quoted"`, Content: "printf 'a\\tb'\techo\nls"}
+			records := snippetRecords([]*s.Snippet{snippet})
+
+			Expect(encode(&out, FORMAT_TSV, (&snippetRecord{}).header(), records)).To(Succeed())
+
+			lines := bytes.Split(bytes.TrimSuffix(out.Bytes(), []byte("\n")), []byte("\n"))
+			Expect(lines).To(HaveLen(2))
+
+			fields := bytes.Split(lines[1], []byte("\t"))
+			Expect(fields).To(HaveLen(10))
+			Expect(string(fields[1])).To(Equal(`"quoted"`))
+			Expect(string(fields[2])).To(Equal(`printf 'a\\tb'\techo\nls`))
+		})
+	})
+})
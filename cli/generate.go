@@ -2,26 +2,79 @@ package cli
 
 import (
 	"fmt"
-	"github.com/atotto/clipboard"
+	"github.com/baopham/snip/clipboard"
+	s "github.com/baopham/snip/snippet"
+	"github.com/baopham/snip/util"
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
+	"os"
 )
 
 func Generate(c *cli.Context) error {
-	content, err := getSnippetContent(c)
+	snippet, mapper, err := getSnippet(c)
 
 	if err != nil {
 		return err
 	}
 
-	message := fmt.Sprintf("`%s` has been saved to your clipboard", content)
-	err = clipboard.WriteAll(content)
+	return output(c, snippet, mapper)
+}
+
+// output prints the content built with the mapper or saves it to the clipboard,
+// the clipboard is cleared like for the sensitive snippets when it holds secrets
+func output(c *cli.Context, snippet *s.Snippet, mapper map[string]string) error {
+	content := snippet.Build(mapper)
+
+	if shouldPrint(c) {
+		return printContent(c, content)
+	}
+
+	provider, err := getClipboard(c)
 
 	if err != nil {
 		return err
 	}
 
-	color.Green(message)
+	clearAfter := c.Duration("clear-after")
+
+	if clearAfter == 0 && (snippet.Sensitive || hasSecrets(snippet)) {
+		clearAfter = DefaultClearAfter
+	}
+
+	if clearAfter > 0 {
+		return writeSensitive(c, provider, snippet, content, clearAfter)
+	}
+
+	err = provider.Write(content)
+
+	if err != nil {
+		return err
+	}
+
+	if provider.Name() != clipboard.STDOUT {
+		color.Green(fmt.Sprintf("`%s` has been saved to your clipboard (%s)", display(snippet, mapper), provider.Name()))
+	}
 
 	return nil
 }
+
+// shouldPrint tells if the content goes to stdout instead of the clipboard:
+// when asked to, or when stdout is piped and no clipboard was chosen
+func shouldPrint(c *cli.Context) bool {
+	if c.Bool("print") || c.Bool("null") {
+		return true
+	}
+
+	return !c.GlobalIsSet("clipboard") && !util.IsTerminal(os.Stdout)
+}
+
+// printContent writes exactly the content, NUL terminated with --null
+func printContent(c *cli.Context, content string) error {
+	if c.Bool("null") {
+		content += "\x00"
+	}
+
+	_, err := fmt.Fprint(os.Stdout, content)
+
+	return err
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baopham/snip/history"
+	"github.com/urfave/cli"
+)
+
+// numberedEntry is a history entry with its position in the history log,
+// which is the number accepted by `snip again`
+type numberedEntry struct {
+	Number        int `json:"number" yaml:"number"`
+	history.Entry `yaml:",inline"`
+}
+
+func (e *numberedEntry) header() []string {
+	return []string{"number", "time", "keyword", "placeholders", "command", "dir", "exit_code", "duration"}
+}
+
+func (e *numberedEntry) values() []string {
+	placeholders := make([]string, 0, len(e.Placeholders))
+
+	for k, v := range e.Placeholders {
+		placeholders = append(placeholders, k+"="+v)
+	}
+
+	sort.Strings(placeholders)
+
+	return []string{
+		fmt.Sprint(e.Number),
+		e.Time.Format(time.RFC3339),
+		e.Keyword,
+		strings.Join(placeholders, " "),
+		e.Command,
+		e.Dir,
+		fmt.Sprint(e.ExitCode),
+		fmt.Sprint(int64(e.Duration)),
+	}
+}
+
+func History(c *cli.Context) error {
+	filePath, err := history.HistoryFile()
+
+	if err != nil {
+		return err
+	}
+
+	entries, err := history.Load(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	keyword := strings.TrimSpace(c.String("keyword"))
+	matches := make([]*numberedEntry, 0)
+
+	for i, entry := range entries {
+		if keyword != "" && entry.Keyword != keyword {
+			continue
+		}
+
+		if c.Bool("failed") && entry.ExitCode == 0 {
+			continue
+		}
+
+		matches = append(matches, &numberedEntry{Number: i + 1, Entry: *entry})
+	}
+
+	if limit := c.Int("limit"); limit > 0 && len(matches) > limit {
+		matches = matches[len(matches)-limit:]
+	}
+
+	records := make([]item, len(matches))
+
+	for i, entry := range matches {
+		records[i] = entry
+	}
+
+	return render(c, (&numberedEntry{}).header(), records, func() error {
+		columns := []tableColumn{
+			{Header: "#", Fixed: true},
+			{Header: "Time", Fixed: true},
+			{Header: "Keyword", Fixed: true},
+			{Header: "Command"},
+			{Header: "Exit", Fixed: true},
+			{Header: "Duration", Fixed: true},
+			{Header: "Dir"},
+		}
+
+		rows := make([][]string, len(matches))
+
+		for i, entry := range matches {
+			rows[i] = []string{
+				fmt.Sprint(entry.Number),
+				entry.Time.Format("2006-01-02 15:04:05"),
+				entry.Keyword,
+				entry.Command,
+				fmt.Sprint(entry.ExitCode),
+				entry.Duration.Round(time.Millisecond).String(),
+				entry.Dir,
+			}
+		}
+
+		return writeTable(c, columns, rows)
+	})
+}
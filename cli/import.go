@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/baopham/snip/importer"
+	s "github.com/baopham/snip/snippet"
+	"github.com/baopham/snip/util"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+// Import merges the snippets of another library or snippet manager, e.g. snip import --from pet snippet.toml.
+// The changes are previewed and confirmed before being saved
+func Import(c *cli.Context) error {
+	source, fileName := strings.TrimSpace(c.String("from")), strings.TrimSpace(c.Args().First())
+
+	if fileName == "" {
+		return MissingInfoError{Message: "Please specify the file to import"}
+	}
+
+	file, err := os.Open(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer util.Check(file.Close)
+
+	result, err := importer.Read(source, file)
+
+	if err != nil {
+		return err
+	}
+
+	global, err := openLibrary(c)
+
+	if err != nil {
+		return err
+	}
+
+	filePath := global.Global()
+	library, err := s.GetAll(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	policy := strings.ToLower(strings.TrimSpace(c.String("on-conflict")))
+	changes, err := planMerge(result.Snippets, library, policy, c.String("suffix"))
+
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		printChange(change)
+	}
+
+	for _, skip := range result.Skipped {
+		color.Yellow("Skipped %s: %s", skip.Name, skip.Reason)
+	}
+
+	// the conflicts are asked once the whole preview is shown
+	if err := resolveConflicts(changes, library, c.String("suffix")); err != nil {
+		return err
+	}
+
+	dryRun := c.Bool("dry-run")
+
+	printMergeSummary(changes, dryRun)
+
+	if dryRun || !hasUpdates(changes) {
+		return nil
+	}
+
+	if !confirm(c, "Save these changes?") {
+		return nil
+	}
+
+	return applyMerge(changes, filePath)
+}
+
+func hasUpdates(changes []*mergeChange) bool {
+	for _, change := range changes {
+		if change.Action != CHANGE_SKIP {
+			return true
+		}
+	}
+
+	return false
+}
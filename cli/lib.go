@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baopham/snip/config"
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+// libraryRecord is a named library in the output of snip lib list
+type libraryRecord struct {
+	Name     string `json:"name" yaml:"name"`
+	Default  bool   `json:"default" yaml:"default"`
+	Snippets int    `json:"snippets" yaml:"snippets"`
+	File     string `json:"file" yaml:"file"`
+}
+
+func (r *libraryRecord) header() []string {
+	return []string{"name", "default", "snippets", "file"}
+}
+
+func (r *libraryRecord) values() []string {
+	return []string{r.Name, fmt.Sprint(r.Default), fmt.Sprint(r.Snippets), r.File}
+}
+
+func LibList(c *cli.Context) error {
+	names, err := s.Libraries()
+
+	if err != nil {
+		return err
+	}
+
+	current := conf.Value(config.LIBRARY)
+	records := make([]item, len(names))
+	libraries := make([]*libraryRecord, len(names))
+
+	for i, name := range names {
+		file, err := s.LibraryFile(name)
+
+		if err != nil {
+			return err
+		}
+
+		snippets, err := s.GetAll(file)
+
+		if err != nil {
+			return err
+		}
+
+		libraries[i] = &libraryRecord{Name: name, Default: name == current, Snippets: len(snippets), File: file}
+		records[i] = libraries[i]
+	}
+
+	return render(c, (&libraryRecord{}).header(), records, func() error {
+		columns := []tableColumn{
+			{Header: "", Fixed: true},
+			{Header: "Name", Fixed: true},
+			{Header: "Snippets", Fixed: true},
+			{Header: "File"},
+		}
+
+		rows := make([][]string, len(libraries))
+
+		for i, library := range libraries {
+			marker := ""
+
+			if library.Default {
+				marker = "*"
+			}
+
+			rows[i] = []string{marker, library.Name, fmt.Sprint(library.Snippets), library.File}
+		}
+
+		return writeTable(c, columns, rows)
+	})
+}
+
+func LibCreate(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" {
+		return MissingInfoError{Message: "Please specify the library name"}
+	}
+
+	if _, err := s.CreateLibrary(name); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("Library '%s' is created", name))
+
+	return nil
+}
+
+func LibDelete(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" {
+		return MissingInfoError{Message: "Please specify the library name"}
+	}
+
+	file, err := s.LibraryFile(name)
+
+	if err != nil {
+		return err
+	}
+
+	snippets, err := s.GetAll(file)
+
+	if err != nil {
+		return err
+	}
+
+	if name == conf.Value(config.LIBRARY) {
+		return s.DefaultLibraryDeleteError{Name: name}
+	}
+
+	message := fmt.Sprintf("Delete the library '%s' and its %d snippets?", name, len(snippets))
+
+	if len(snippets) > 0 && !confirm(c, message) {
+		return nil
+	}
+
+	if err := s.DeleteLibrary(name); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("Library '%s' is deleted", name))
+
+	return nil
+}
+
+// LibDefault prints the default library, or sets it in the config when a name is given
+func LibDefault(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" {
+		fmt.Println(conf.Value(config.LIBRARY))
+
+		return nil
+	}
+
+	exists, err := s.LibraryExists(name)
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return s.LibraryNotFoundError{Name: name}
+	}
+
+	return saveSetting(config.LIBRARY, name)
+}
+
+func LibMove(c *cli.Context) error {
+	return transferSnippet(c, true)
+}
+
+func LibCopy(c *cli.Context) error {
+	return transferSnippet(c, false)
+}
+
+// transferSnippet copies the snippet of the library chosen with --lib to another library,
+// removing it from its file when moved
+func transferSnippet(c *cli.Context, move bool) error {
+	keyword := strings.TrimSpace(c.Args().Get(0))
+	target := strings.TrimSpace(c.Args().Get(1))
+
+	if keyword == "" || target == "" {
+		return MissingInfoError{Message: "Please specify the snippet keyword and the library"}
+	}
+
+	exists, err := s.LibraryExists(target)
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return s.LibraryNotFoundError{Name: target}
+	}
+
+	snippet, err := findRawSnippet(c, keyword)
+
+	if err != nil {
+		return err
+	}
+
+	targetFile, err := s.LibraryFile(target)
+
+	if err != nil {
+		return err
+	}
+
+	source := snippet.Source
+
+	if err := snippet.Save(targetFile); err != nil {
+		return err
+	}
+
+	if !move {
+		color.Green(fmt.Sprintf("Snippet '%s' is copied to the library '%s'", snippet.Keyword, target))
+		return nil
+	}
+
+	if err := snippet.Remove(source); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("Snippet '%s' is moved to the library '%s'", snippet.Keyword, target))
+
+	return nil
+}
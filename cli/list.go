@@ -1,38 +1,196 @@
 package cli
 
 import (
+	"github.com/baopham/snip/config"
+	"github.com/baopham/snip/highlight"
 	s "github.com/baopham/snip/snippet"
-	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
+// snippetColumn is a column of the snippet tables
+type snippetColumn struct {
+	tableColumn
+	value func(snippet *s.Snippet) string
+	// code columns are highlighted as the language of the snippet
+	code bool
+}
+
+var snippetColumns = map[string]snippetColumn{
+	"keyword": {
+		tableColumn{Header: "Keyword", Fixed: true},
+		func(snippet *s.Snippet) string { return snippet.Keyword },
+		false,
+	},
+	"content": {
+		tableColumn{Header: "Content"},
+		func(snippet *s.Snippet) string { return snippet.Content },
+		true,
+	},
+	"description": {
+		tableColumn{Header: "Description", Wrap: true},
+		func(snippet *s.Snippet) string { return snippet.Description },
+		false,
+	},
+	"tags": {
+		tableColumn{Header: "Tags", Wrap: true},
+		func(snippet *s.Snippet) string { return strings.Join(snippet.Tags, ", ") },
+		false,
+	},
+	"type": {
+		tableColumn{Header: "Type", Fixed: true},
+		func(snippet *s.Snippet) string { return string(snippet.Type) },
+		false,
+	},
+	"source": {
+		tableColumn{Header: "Source", Fixed: true},
+		func(snippet *s.Snippet) string { return sourceLabel(snippet.Source) },
+		false,
+	},
+}
+
+// SOURCE_GLOBAL is the source of the snippets of the global library
+const SOURCE_GLOBAL = "global"
+
+var columnAliases = map[string]string{
+	"desc": "description",
+}
+
 func List(c *cli.Context) error {
-	filePath, err := s.SnippetFile()
+	library, err := openLibrary(c)
 
 	if err != nil {
 		return err
 	}
 
-	snippets, err := s.GetAll(filePath)
+	snippets, err := library.GetAll()
 
 	if err != nil {
 		return err
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Keyword", "Content", "Description"})
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	return renderSnippets(c, visible(c, snippets), "")
+}
+
+// sourceLabel returns global for the default library, the name of the other libraries
+// and the path relative to the current directory for a project file
+func sourceLabel(source string) string {
+	if source == "" {
+		return SOURCE_GLOBAL
+	}
+
+	if name := s.LibraryName(source); name == s.DEFAULT_LIBRARY {
+		return SOURCE_GLOBAL
+	} else if name != "" {
+		return name
+	}
+
+	if dir, err := os.Getwd(); err == nil {
+		if relative, err := filepath.Rel(dir, source); err == nil {
+			return relative
+		}
+	}
+
+	return source
+}
+
+// visible hides the sensitive snippets unless --reveal is given
+func visible(c *cli.Context, snippets []*s.Snippet) []*s.Snippet {
+	found := make([]*s.Snippet, 0, len(snippets))
+
+	for _, snippet := range snippets {
+		if snippet.Sensitive && !c.Bool("reveal") {
+			continue
+		}
+
+		found = append(found, snippet)
+	}
+
+	return found
+}
+
+// renderSnippets outputs the snippets, the runes matching the search query stand out in the table
+func renderSnippets(c *cli.Context, snippets []*s.Snippet, query string) error {
+	return render(c, (&snippetRecord{}).header(), snippetRecords(snippets), func() error {
+		columns, err := getColumns(c, snippets)
+
+		if err != nil {
+			return err
+		}
+
+		tableColumns := make([]tableColumn, len(columns))
+
+		for i, column := range columns {
+			code := column.code
+			tableColumns[i] = column.tableColumn
+			tableColumns[i].Highlight = func(row int, cell string) string {
+				language := ""
+
+				if code {
+					language = highlight.Detect(snippets[row].Content)
+				}
+
+				return highlight.Highlight(cell, language, highlight.Match(query, cell, conf.Value(config.SEARCH)))
+			}
+		}
+
+		rows := make([][]string, len(snippets))
+
+		for i, snippet := range snippets {
+			rows[i] = make([]string, len(columns))
+
+			for j, column := range columns {
+				rows[i][j] = column.value(snippet)
+			}
+		}
+
+		return writeTable(c, tableColumns, rows)
+	})
+}
+
+// getColumns returns the columns chosen with --columns, e.g. keyword,desc,tags.
+// By default: keyword, content, description, the tags when there are some
+// and the source when some snippets come from a project
+func getColumns(c *cli.Context, snippets []*s.Snippet) ([]snippetColumn, error) {
+	names := []string{"keyword", "content", "description"}
 
 	for _, snippet := range snippets {
-		table.Append([]string{
-			snippet.Keyword,
-			snippet.Content,
-			snippet.Description,
-		})
+		if len(snippet.Tags) > 0 {
+			names = append(names, "tags")
+			break
+		}
 	}
 
-	table.Render()
+	for _, snippet := range snippets {
+		if sourceLabel(snippet.Source) != SOURCE_GLOBAL {
+			names = append(names, "source")
+			break
+		}
+	}
+
+	if spec := strings.TrimSpace(c.String("columns")); spec != "" {
+		names = strings.Split(spec, ",")
+	}
+
+	columns := make([]snippetColumn, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if alias, ok := columnAliases[name]; ok {
+			name = alias
+		}
+
+		column, ok := snippetColumns[name]
+
+		if !ok {
+			return nil, UnknownColumnError{Column: name}
+		}
+
+		columns = append(columns, column)
+	}
 
-	return nil
+	return columns, nil
 }
@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+)
+
+// matrixRun is one execution of a matrix
+type matrixRun struct {
+	label    string
+	mapper   map[string]string
+	started  bool
+	exitCode int
+	duration time.Duration
+}
+
+func executeMatrix(c *cli.Context, snippet *s.Snippet, values map[string][]string) error {
+	if c.Bool("output") {
+		return MissingInfoError{Message: "--output cannot be used with multiple placeholder values"}
+	}
+
+	mappers := s.ExpandMatrix(values)
+	runs := make([]*matrixRun, len(mappers))
+
+	for i, mapper := range mappers {
+		runs[i] = &matrixRun{label: matrixLabel(redact(snippet, mapper), values), mapper: mapper}
+	}
+
+	if !confirm(c, "Are you sure you want to execute %d runs of: %s", len(runs), snippet.Content) {
+		return nil
+	}
+
+	parallel := c.Int("parallel")
+
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	// Ctrl-C and SIGTERM cancel the runs too, the process groups of --fail-fast do not get the signals of the terminal
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		sem     = make(chan struct{}, parallel)
+		stdout  = &lockedWriter{w: os.Stdout, mu: &mu}
+		stderr  = &lockedWriter{w: os.Stderr, mu: &mu}
+		failed  bool
+		failedM sync.Mutex
+	)
+
+	for _, next := range runs {
+		sem <- struct{}{}
+
+		failedM.Lock()
+		stop := failed && c.Bool("fail-fast") || ctx.Err() != nil
+		failedM.Unlock()
+
+		if stop {
+			<-sem
+			break
+		}
+
+		next.started = true
+		wg.Add(1)
+
+		go func(r *matrixRun) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			prefix := fmt.Sprintf("[%s] ", r.label)
+			out := &prefixWriter{w: stdout, prefix: prefix}
+			errOut := &prefixWriter{w: stderr, prefix: prefix}
+
+			cmd := exec.CommandContext(ctx, shell(), "-c", snippet.Build(r.mapper))
+			cmd.Stdout = out
+			cmd.Stderr = errOut
+			// the output pipes are not waited for once the run is killed
+			cmd.WaitDelay = time.Second
+
+			// the runs stay in the foreground group of the terminal unless the failed run kills the others
+			if c.Bool("fail-fast") {
+				inProcessGroup(cmd)
+			}
+
+			start := time.Now()
+			err := run(cmd, snippet, r.mapper)
+			r.duration = time.Since(start)
+
+			for _, w := range []*prefixWriter{out, errOut} {
+				if ferr := w.Flush(); ferr != nil && err == nil {
+					err = ferr
+				}
+			}
+
+			r.exitCode = exitCode(err)
+
+			if err != nil {
+				failedM.Lock()
+				failed = true
+				failedM.Unlock()
+
+				if c.Bool("fail-fast") {
+					cancel()
+				}
+			}
+		}(next)
+	}
+
+	wg.Wait()
+
+	return matrixSummary(runs)
+}
+
+// matrixLabel describes a run by the placeholders that vary between runs, the mapper has its secrets redacted
+func matrixLabel(mapper map[string]string, values map[string][]string) string {
+	names := make([]string, 0, len(mapper))
+
+	for name := range mapper {
+		if len(values[name]) > 1 {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+
+	for _, name := range names {
+		parts = append(parts, name+"="+mapper[name])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func matrixSummary(runs []*matrixRun) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Run", "Exit", "Duration"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	failed := 0
+
+	for _, r := range runs {
+		exit, duration := "skipped", "-"
+
+		if r.started {
+			exit, duration = fmt.Sprint(r.exitCode), r.duration.Round(time.Millisecond).String()
+		}
+
+		if !r.started || r.exitCode != 0 {
+			failed++
+		}
+
+		table.Append([]string{r.label, exit, duration})
+	}
+
+	fmt.Println()
+	table.Render()
+
+	if failed > 0 {
+		return MatrixError{Failed: failed, Total: len(runs)}
+	}
+
+	color.Green(fmt.Sprintf("All %d runs succeeded", len(runs)))
+
+	return nil
+}
+
+// lockedWriter serializes the writes of concurrent runs
+type lockedWriter struct {
+	w  io.Writer
+	mu *sync.Mutex
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// prefixWriter writes every complete line with the given prefix
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+
+	for {
+		i := bytes.IndexByte(p.buf.Bytes(), '\n')
+
+		if i < 0 {
+			return len(b), nil
+		}
+
+		line := p.buf.Next(i + 1)
+
+		if _, err := p.w.Write(append([]byte(p.prefix), line...)); err != nil {
+			return len(b), err
+		}
+	}
+}
+
+// Flush writes the remaining incomplete line, if any
+func (p *prefixWriter) Flush() error {
+	if p.buf.Len() == 0 {
+		return nil
+	}
+
+	line := append([]byte(p.prefix), p.buf.Bytes()...)
+	p.buf.Reset()
+
+	_, err := p.w.Write(append(line, '\n'))
+
+	return err
+}
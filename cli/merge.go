@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+)
+
+const (
+	CONFLICT_SKIP        = "skip"
+	CONFLICT_OVERWRITE   = "overwrite"
+	CONFLICT_RENAME      = "rename"
+	CONFLICT_KEEP_NEWER  = "keep-newer"
+	CONFLICT_INTERACTIVE = "interactive"
+)
+
+var conflictPolicies = []string{CONFLICT_SKIP, CONFLICT_OVERWRITE, CONFLICT_RENAME, CONFLICT_KEEP_NEWER, CONFLICT_INTERACTIVE}
+
+const (
+	CHANGE_ADD    = "added"
+	CHANGE_UPDATE = "updated"
+	CHANGE_SKIP   = "skipped"
+)
+
+// mergeChange is what merging a snippet does to the library
+type mergeChange struct {
+	Action  string
+	Snippet *s.Snippet
+	// Existing is the saved snippet with the same keyword
+	Existing *s.Snippet
+	Reason   string
+	// Conflict tells the change is asked once the preview is shown, with the interactive policy
+	Conflict bool
+}
+
+// planMerge decides what to do with each incoming snippet, the policy resolves
+// the snippets whose keyword is already saved with a different content.
+// With the interactive policy the conflicts are left to resolveConflicts
+func planMerge(incoming, library []*s.Snippet, policy, suffix string) ([]*mergeChange, error) {
+	if !contains(conflictPolicies, policy) {
+		return nil, UnknownConflictPolicyError{Policy: policy}
+	}
+
+	existing := make(map[string]*s.Snippet)
+	taken := make(map[string]bool)
+
+	for _, snippet := range library {
+		existing[snippet.Keyword] = snippet
+		taken[snippet.Keyword] = true
+	}
+
+	var changes []*mergeChange
+
+	for _, snippet := range incoming {
+		found := existing[snippet.Keyword]
+
+		if found == nil && !taken[snippet.Keyword] {
+			taken[snippet.Keyword] = true
+			changes = append(changes, &mergeChange{Action: CHANGE_ADD, Snippet: snippet})
+			continue
+		}
+
+		if found == nil {
+			changes = append(changes, &mergeChange{Action: CHANGE_SKIP, Snippet: snippet, Reason: "imported twice"})
+			continue
+		}
+
+		if sameSnippet(found, snippet) {
+			changes = append(changes, &mergeChange{Action: CHANGE_SKIP, Snippet: snippet, Existing: found, Reason: "identical"})
+			continue
+		}
+
+		change := &mergeChange{Action: CHANGE_UPDATE, Snippet: snippet, Existing: found}
+
+		if policy == CONFLICT_INTERACTIVE {
+			change.Conflict, change.Reason = true, "conflict"
+		} else {
+			resolve(change, policy, suffix, taken)
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// resolveConflicts asks what to do with each conflict left by the interactive policy
+func resolveConflicts(changes []*mergeChange, library []*s.Snippet, suffix string) error {
+	taken := make(map[string]bool)
+
+	for _, snippet := range library {
+		taken[snippet.Keyword] = true
+	}
+
+	for _, change := range changes {
+		if change.Action == CHANGE_ADD {
+			taken[change.Snippet.Keyword] = true
+		}
+	}
+
+	for _, change := range changes {
+		if !change.Conflict {
+			continue
+		}
+
+		color.Yellow("~ %s", change.Snippet.Keyword)
+
+		resolution, err := promptChoice("[s]kip, [o]verwrite, [r]ename or [k]eep the newer?", CONFLICT_SKIP, CONFLICT_OVERWRITE, CONFLICT_RENAME, CONFLICT_KEEP_NEWER)
+
+		if err != nil {
+			return err
+		}
+
+		change.Conflict, change.Reason = false, ""
+		resolve(change, resolution, suffix, taken)
+	}
+
+	return nil
+}
+
+// resolve applies the conflict policy to the update of an existing snippet
+func resolve(change *mergeChange, policy, suffix string, taken map[string]bool) {
+	snippet := change.Snippet
+
+	switch policy {
+	case CONFLICT_SKIP:
+		change.Action, change.Reason = CHANGE_SKIP, "already exists"
+	case CONFLICT_RENAME:
+		renamed := *snippet
+		renamed.Keyword = snippet.Keyword + "-" + suffix
+
+		for i := 2; taken[renamed.Keyword]; i++ {
+			renamed.Keyword = fmt.Sprintf("%s-%s-%d", snippet.Keyword, suffix, i)
+		}
+
+		taken[renamed.Keyword] = true
+		*change = mergeChange{Action: CHANGE_ADD, Snippet: &renamed, Reason: "renamed from " + snippet.Keyword}
+	case CONFLICT_KEEP_NEWER:
+		if !snippet.Updated.After(change.Existing.Updated) {
+			change.Action, change.Reason = CHANGE_SKIP, "the saved snippet is newer"
+		}
+	}
+}
+
+// applyMerge saves the added and updated snippets at once, none is saved if any cannot be
+func applyMerge(changes []*mergeChange, filePath string) error {
+	var added, replaced []*s.Snippet
+
+	for _, change := range changes {
+		switch change.Action {
+		case CHANGE_ADD:
+			added = append(added, change.Snippet)
+		case CHANGE_UPDATE:
+			updated := *change.Snippet
+
+			// an encrypted snippet stays encrypted when overwritten
+			updated.Encrypted = updated.Encrypted || change.Existing.Encrypted
+			replaced = append(replaced, &updated)
+		}
+	}
+
+	return s.Merge(filePath, added, replaced)
+}
+
+// printChange shows the change as a diff: + for the added snippets, ~ for the updated ones
+func printChange(change *mergeChange) {
+	reason := ""
+
+	if change.Reason != "" {
+		reason = " (" + change.Reason + ")"
+	}
+
+	switch change.Action {
+	case CHANGE_ADD:
+		color.Green("+ %s: %s%s", change.Snippet.Keyword, previewContent(change.Snippet), reason)
+	case CHANGE_SKIP:
+		fmt.Printf("= %s%s\n", change.Snippet.Keyword, reason)
+	case CHANGE_UPDATE:
+		color.Yellow("~ %s%s", change.Snippet.Keyword, reason)
+
+		before, after := snippetFields(change.Existing), snippetFields(change.Snippet)
+		before[0], after[0] = previewContent(change.Existing), previewContent(change.Snippet)
+
+		for i, field := range []string{"content", "description", "type", "sensitive", "tags"} {
+			if before[i] != after[i] || (i == 0 && change.Snippet.Content != change.Existing.Content) {
+				color.Red("    - %s: %s", field, before[i])
+				color.Green("    + %s: %s", field, after[i])
+			}
+		}
+	}
+}
+
+// printMergeSummary reports how many snippets were added, updated and skipped
+func printMergeSummary(changes []*mergeChange, dryRun bool) {
+	counts := make(map[string]int)
+
+	for _, change := range changes {
+		counts[change.Action]++
+	}
+
+	summary := fmt.Sprintf("%d added, %d updated, %d skipped", counts[CHANGE_ADD], counts[CHANGE_UPDATE], counts[CHANGE_SKIP])
+
+	if dryRun {
+		summary += ", nothing saved (dry run)"
+	}
+
+	color.Cyan(summary)
+}
+
+func sameSnippet(a, b *s.Snippet) bool {
+	return strings.Join(snippetFields(a), "\x00") == strings.Join(snippetFields(b), "\x00")
+}
+
+// snippetFields returns the content, description, type, sensitive and tags compared when merging
+func snippetFields(snippet *s.Snippet) []string {
+	return []string{
+		snippet.Content,
+		snippet.Description,
+		string(snippet.Type),
+		fmt.Sprint(snippet.Sensitive),
+		strings.Join(snippet.Tags, ","),
+	}
+}
+
+func previewContent(snippet *s.Snippet) string {
+	if snippet.Sensitive {
+		return "********"
+	}
+
+	return oneLine(snippet.Content)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
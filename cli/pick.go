@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	s "github.com/baopham/snip/snippet"
+	"github.com/urfave/cli"
+)
+
+// Pick lets the user choose a snippet, asks its placeholders and outputs it like Generate.
+// The prompts use the terminal so that the output can be captured by the shell widgets
+func Pick(c *cli.Context) error {
+	if err := useTerminal(); err != nil {
+		return err
+	}
+
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return err
+	}
+
+	snippets, err := library.GetAll()
+
+	if err != nil {
+		return err
+	}
+
+	if len(snippets) == 0 {
+		return MissingInfoError{Message: "No snippet saved yet"}
+	}
+
+	picked, err := pickSnippet(snippets, strings.TrimSpace(c.Args().First()), c.Bool("reveal"))
+
+	if err != nil || picked == nil {
+		return err
+	}
+
+	snippet, err := findSnippet(c, picked.Keyword)
+
+	if err != nil {
+		return err
+	}
+
+	mapper := getPlaceholderMapper(c.Args())
+
+	if err = withProfile(c, snippet, mapper); err != nil {
+		return err
+	}
+
+	if err = withSecrets(c, snippet, mapper); err != nil {
+		return err
+	}
+
+	if err = promptMissing(snippet, mapper); err != nil {
+		return err
+	}
+
+	return output(c, snippet, mapper)
+}
+
+// pickSnippet uses fzf when installed, a numbered menu otherwise. Returns nil when cancelled
+func pickSnippet(snippets []*s.Snippet, query string, reveal bool) (*s.Snippet, error) {
+	if _, err := exec.LookPath("fzf"); err == nil {
+		return pickWithFzf(snippets, query, reveal)
+	}
+
+	for i, snippet := range snippets {
+		fmt.Fprintf(os.Stderr, "%3d) %s: %s\n", i+1, snippet.Keyword, pickContent(snippet, reveal))
+	}
+
+	answer, err := promptValue("Snippet number", false)
+
+	if err != nil || answer == "" {
+		return nil, err
+	}
+
+	i, err := strconv.Atoi(answer)
+
+	if err != nil || i < 1 || i > len(snippets) {
+		return nil, NotFoundSnippet{Keyword: answer}
+	}
+
+	return snippets[i-1], nil
+}
+
+func pickWithFzf(snippets []*s.Snippet, query string, reveal bool) (*s.Snippet, error) {
+	lines := &bytes.Buffer{}
+
+	for i, snippet := range snippets {
+		fmt.Fprintf(lines, "%d\t%s\t%s\t%s\n", i, snippet.Keyword, snippet.Description, pickContent(snippet, reveal))
+	}
+
+	out := &bytes.Buffer{}
+
+	cmd := exec.Command("fzf", "--height=40%", "--reverse", "--delimiter=\t", "--with-nth=2..", "--query="+query)
+	cmd.Stdin = lines
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		// fzf exits with 130 when cancelled and 1 without match
+		if code := exitCode(err); code == 130 || code == 1 {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	i, err := strconv.Atoi(strings.SplitN(out.String(), "\t", 2)[0])
+
+	if err != nil || i < 0 || i >= len(snippets) {
+		return nil, nil
+	}
+
+	return snippets[i], nil
+}
+
+func oneLine(content string) string {
+	return strings.Replace(content, "\n", " ⏎ ", -1)
+}
+
+// pickContent returns the content shown to pick the snippet, masked when it is sensitive unless --reveal
+func pickContent(snippet *s.Snippet, reveal bool) string {
+	if snippet.Sensitive && !reveal {
+		return "********"
+	}
+
+	return oneLine(snippet.Content)
+}
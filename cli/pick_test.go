@@ -0,0 +1,18 @@
+package cli
+
+import (
+	s "github.com/baopham/snip/snippet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pick", func() {
+	Context("when calling pickContent()", func() {
+		It("should mask the content of a sensitive snippet", func() {
+			snippet := &s.Snippet{Keyword: "token", Content: "export TOKEN=s3cret", Sensitive: true}
+
+			Expect(pickContent(snippet, false)).To(Equal("********"))
+			Expect(pickContent(snippet, true)).To(Equal("export TOKEN=s3cret"))
+		})
+	})
+})
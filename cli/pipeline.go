@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+
+	s "github.com/baopham/snip/snippet"
+	"github.com/urfave/cli"
+)
+
+// pipelineStage is one snippet of a pipeline
+type pipelineStage struct {
+	snippet *s.Snippet
+	mapper  map[string]string
+	cmd     *exec.Cmd
+}
+
+func isPipeline(c *cli.Context) bool {
+	return strings.Contains(c.Args().First(), "|")
+}
+
+// executePipeline runs `snip x 'pods ns=web | grep-err | count'`: every stage is a saved snippet
+// started in its own process, the stdout of a stage feeding the stdin of the next one
+func executePipeline(c *cli.Context) error {
+	for _, flag := range []string{"output", "matrix"} {
+		if c.Bool(flag) {
+			return MissingInfoError{Message: "--" + flag + " cannot be used with a pipeline"}
+		}
+	}
+
+	stages, err := getStages(c)
+
+	if err != nil {
+		return err
+	}
+
+	contents := make([]string, len(stages))
+
+	for i, stage := range stages {
+		contents[i] = display(stage.snippet, stage.mapper)
+		stage.cmd = exec.Command(shell(), "-c", stage.snippet.Build(stage.mapper))
+		stage.cmd.Stderr = os.Stderr
+	}
+
+	if !confirm(c, "Are you sure you want to execute: %s", strings.Join(contents, " | ")) {
+		return nil
+	}
+
+	stages[0].cmd.Stdin = os.Stdin
+	stages[len(stages)-1].cmd.Stdout = os.Stdout
+
+	var pipes []*os.File
+
+	for i := 0; i < len(stages)-1; i++ {
+		r, w, err := os.Pipe()
+
+		if err != nil {
+			return err
+		}
+
+		stages[i].cmd.Stdout = w
+		stages[i+1].cmd.Stdin = r
+		pipes = append(pipes, r, w)
+	}
+
+	started := 0
+
+	for _, stage := range stages {
+		if err = stage.cmd.Start(); err != nil {
+			break
+		}
+
+		started++
+	}
+
+	// the stages own the pipes now, closing them lets each stage see the end of its input
+	for _, pipe := range pipes {
+		pipe.Close()
+	}
+
+	if err != nil {
+		for _, stage := range stages[:started] {
+			stage.cmd.Process.Kill()
+			stage.cmd.Wait()
+		}
+
+		return PipelineError{Stage: started + 1, Keyword: stages[started].snippet.Keyword, Err: err}
+	}
+
+	errs := make([]error, len(stages))
+
+	var wg sync.WaitGroup
+
+	for i, stage := range stages {
+		wg.Add(1)
+
+		go func(i int, stage *pipelineStage) {
+			defer wg.Done()
+			errs[i] = record(stage.snippet, stage.mapper, stage.cmd.Wait)
+		}(i, stage)
+	}
+
+	wg.Wait()
+
+	return pipelineFailure(stages, errs)
+}
+
+// pipelineFailure reports the last stage that failed like bash pipefail, except the
+// stages killed by SIGPIPE: a later stage stopped reading their output, e.g. head
+func pipelineFailure(stages []*pipelineStage, errs []error) error {
+	for i := len(errs) - 1; i >= 0; i-- {
+		if err := errs[i]; err != nil && (i == len(errs)-1 || !brokenPipe(err)) {
+			return PipelineError{Stage: i + 1, Keyword: stages[i].snippet.Keyword, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// brokenPipe tells whether the stage was killed by SIGPIPE, or its shell exited with 128+SIGPIPE
+func brokenPipe(err error) bool {
+	exitErr, ok := err.(*exec.ExitError)
+
+	if !ok {
+		return false
+	}
+
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return status.Signal() == syscall.SIGPIPE
+	}
+
+	return exitErr.ExitCode() == 128+int(syscall.SIGPIPE)
+}
+
+// getStages finds the snippet of every stage. The placeholders given after
+// the pipeline apply to every stage, e.g. snip x 'pods | count' ns=web
+func getStages(c *cli.Context) ([]*pipelineStage, error) {
+	parts, err := splitPipeline(c.Args().First())
+
+	if err != nil {
+		return nil, err
+	}
+
+	shared := getPlaceholderMapper(c.Args())
+	stages := make([]*pipelineStage, len(parts))
+
+	for i, args := range parts {
+		if len(args) == 0 {
+			return nil, MissingInfoError{Message: fmt.Sprintf("Please specify the keyword of stage %d", i+1)}
+		}
+
+		snippet, err := findSnippet(c, args[0])
+
+		if err != nil {
+			return nil, err
+		}
+
+		mapper := make(map[string]string, len(shared))
+
+		for k, v := range shared {
+			mapper[k] = v
+		}
+
+		for k, v := range getPlaceholderMapper(cli.Args(args)) {
+			mapper[k] = v
+		}
+
+		if err := withProfile(c, snippet, mapper); err != nil {
+			return nil, err
+		}
+
+		if err := withSecrets(c, snippet, mapper); err != nil {
+			return nil, err
+		}
+
+		stages[i] = &pipelineStage{snippet: snippet, mapper: mapper}
+	}
+
+	return stages, nil
+}
+
+// splitPipeline splits the pipeline into the arguments of each stage,
+// honoring single quotes, double quotes and backslash escapes
+func splitPipeline(pipeline string) ([][]string, error) {
+	var (
+		stages  [][]string
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	endArg := func() {
+		if inArg {
+			args = append(args, current.String())
+			current.Reset()
+			inArg = false
+		}
+	}
+
+	for _, r := range pipeline {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '|':
+			endArg()
+			stages = append(stages, args)
+			args = nil
+		case r == ' ' || r == '\t' || r == '\n':
+			endArg()
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, MissingInfoError{Message: "Unterminated quote or escape in the pipeline: " + pipeline}
+	}
+
+	endArg()
+
+	return append(stages, args), nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"os/exec"
+
+	s "github.com/baopham/snip/snippet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pipeline", func() {
+	run := func(script string) error {
+		return exec.Command("sh", "-c", script).Run()
+	}
+
+	stages := func(keywords ...string) []*pipelineStage {
+		result := make([]*pipelineStage, len(keywords))
+
+		for i, keyword := range keywords {
+			result[i] = &pipelineStage{snippet: &s.Snippet{Keyword: keyword}}
+		}
+
+		return result
+	}
+
+	Context("when calling pipelineFailure()", func() {
+		It("should report the last stage that failed", func() {
+			err := pipelineFailure(stages("pods", "grep-err", "count"), []error{run("exit 2"), run("exit 1"), nil})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.(PipelineError).Stage).To(Equal(2))
+			Expect(err.(PipelineError).Keyword).To(Equal("grep-err"))
+		})
+
+		It("should not report an upstream stage killed by SIGPIPE", func() {
+			Expect(pipelineFailure(stages("logs", "first"), []error{run("kill -PIPE $$"), nil})).To(BeNil())
+			Expect(pipelineFailure(stages("logs", "first"), []error{run("exit 141"), nil})).To(BeNil())
+		})
+
+		It("should report the last stage killed by SIGPIPE", func() {
+			err := pipelineFailure(stages("logs", "first"), []error{nil, run("kill -PIPE $$")})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.(PipelineError).Stage).To(Equal(2))
+		})
+	})
+
+	Context("when calling executePipeline()", func() {
+		It("should refuse --output and --matrix", func() {
+			for _, flag := range []string{"output", "matrix"} {
+				c := newContext(map[string]string{flag: "true"}, "pods | count")
+
+				Expect(executePipeline(c)).To(MatchError("--" + flag + " cannot be used with a pipeline"))
+			}
+		})
+	})
+
+	Context("when calling splitPipeline()", func() {
+		It("should split the stages and keep the quoted pipes", func() {
+			stages, err := splitPipeline(`pods ns=web | grep-err pattern='a|b' | count`)
+
+			Expect(err).To(BeNil())
+			Expect(stages).To(Equal([][]string{{"pods", "ns=web"}, {"grep-err", "pattern=a|b"}, {"count"}}))
+		})
+	})
+})
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package cli
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// inProcessGroup starts the command in its own process group, cancelling it kills the whole group
+// so the processes started by the shell do not keep running. The group does not get the signals
+// of the terminal, the matrix cancels it on SIGINT and SIGTERM
+func inProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
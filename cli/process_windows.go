@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package cli
+
+import "os/exec"
+
+// inProcessGroup is a no-op on Windows, cancelling the command kills the shell only
+func inProcessGroup(cmd *exec.Cmd) {}
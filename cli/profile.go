@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/baopham/snip/config"
+	"github.com/baopham/snip/profile"
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+// profileRecord is a profile in the output of snip profile list and show
+type profileRecord struct {
+	Name   string            `json:"name" yaml:"name"`
+	Active bool              `json:"active" yaml:"active"`
+	Values map[string]string `json:"values" yaml:"values"`
+}
+
+func (r *profileRecord) header() []string {
+	return []string{"name", "active", "values"}
+}
+
+func (r *profileRecord) values() []string {
+	return []string{r.Name, fmt.Sprint(r.Active), formatValues(r.Values)}
+}
+
+func ProfileList(c *cli.Context) error {
+	profiles, err := loadProfiles()
+
+	if err != nil {
+		return err
+	}
+
+	names := profiles.Names()
+	records := make([]item, len(names))
+	rows := make([][]string, len(names))
+
+	for i, name := range names {
+		r := &profileRecord{Name: name, Active: name == profileName(c), Values: profiles[name]}
+		records[i] = r
+		rows[i] = []string{"", name, formatValues(r.Values)}
+
+		if r.Active {
+			rows[i][0] = "*"
+		}
+	}
+
+	return render(c, (&profileRecord{}).header(), records, func() error {
+		columns := []tableColumn{
+			{Header: "", Fixed: true},
+			{Header: "Name", Fixed: true},
+			{Header: "Values", Wrap: true},
+		}
+
+		return writeTable(c, columns, rows)
+	})
+}
+
+func ProfileShow(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" {
+		name = profileName(c)
+	}
+
+	if name == "" {
+		return MissingInfoError{Message: "Please specify the profile name"}
+	}
+
+	profiles, err := loadProfiles()
+
+	if err != nil {
+		return err
+	}
+
+	values, err := profiles.Get(name)
+
+	if err != nil {
+		return err
+	}
+
+	r := &profileRecord{Name: name, Active: name == profileName(c), Values: values}
+
+	return render(c, r.header(), []item{r}, func() error {
+		names := make([]string, 0, len(values))
+
+		for k := range values {
+			names = append(names, k)
+		}
+
+		sort.Strings(names)
+
+		rows := make([][]string, len(names))
+
+		for i, k := range names {
+			rows[i] = []string{k, values[k]}
+		}
+
+		return writeTable(c, []tableColumn{{Header: "Placeholder", Fixed: true}, {Header: "Value"}}, rows)
+	})
+}
+
+// ProfileSet saves the placeholder values to the profile: snip profile set prod host=db.prod ns=web, host= removes host
+func ProfileSet(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" || len(c.Args()) < 2 {
+		return MissingInfoError{Message: "Please specify the profile and its values: snip profile set prod host=db.prod"}
+	}
+
+	values := make(map[string]string)
+
+	for _, pair := range c.Args().Tail() {
+		parts := strings.SplitN(pair, "=", 2)
+
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return MissingInfoError{Message: fmt.Sprintf("Please specify the value as name=value: %s", pair)}
+		}
+
+		values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	file, err := profile.File()
+
+	if err != nil {
+		return err
+	}
+
+	profiles, err := profile.Load(file)
+
+	if err != nil {
+		return err
+	}
+
+	if err := profiles.Set(name, values); err != nil {
+		return err
+	}
+
+	if err := profiles.Save(file); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("Profile '%s' is saved: %s", name, formatValues(profiles[name])))
+
+	return nil
+}
+
+func ProfileDelete(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" {
+		return MissingInfoError{Message: "Please specify the profile name"}
+	}
+
+	file, err := profile.File()
+
+	if err != nil {
+		return err
+	}
+
+	profiles, err := profile.Load(file)
+
+	if err != nil {
+		return err
+	}
+
+	if err := profiles.Delete(name); err != nil {
+		return err
+	}
+
+	if err := profiles.Save(file); err != nil {
+		return err
+	}
+
+	if name == conf.Value(config.PROFILE) {
+		if err := saveSetting(config.PROFILE, ""); err != nil {
+			return err
+		}
+	}
+
+	color.Green(fmt.Sprintf("Profile '%s' is deleted", name))
+
+	return nil
+}
+
+// ProfileUse prints the active profile, or activates the given one in the config
+func ProfileUse(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" {
+		fmt.Println(profileName(c))
+		return nil
+	}
+
+	profiles, err := loadProfiles()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := profiles.Get(name); err != nil {
+		return err
+	}
+
+	return saveSetting(config.PROFILE, name)
+}
+
+// profileName returns the profile chosen with --profile, the active profile of the config otherwise
+func profileName(c *cli.Context) string {
+	if name := strings.TrimSpace(c.String("profile")); name != "" {
+		return name
+	}
+
+	if name := strings.TrimSpace(c.GlobalString("profile")); name != "" {
+		return name
+	}
+
+	return conf.Value(config.PROFILE)
+}
+
+// profileValues returns the placeholder values of the chosen profile, none without a profile
+func profileValues(c *cli.Context) (map[string]string, error) {
+	name := profileName(c)
+
+	if name == "" {
+		return nil, nil
+	}
+
+	profiles, err := loadProfiles()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles.Get(name)
+}
+
+// withProfile fills the placeholders which were not given with the values of the chosen profile
+func withProfile(c *cli.Context, snippet *s.Snippet, mapper map[string]string) error {
+	values, err := profileValues(c)
+
+	if err != nil {
+		return err
+	}
+
+	snippet.Fill(mapper, values)
+
+	return nil
+}
+
+// profileLabel returns the prefix of the confirmations telling the chosen profile, empty without a profile
+func profileLabel(c *cli.Context) string {
+	if name := profileName(c); name != "" {
+		return fmt.Sprintf("[profile %s] ", name)
+	}
+
+	return ""
+}
+
+func loadProfiles() (profile.Profiles, error) {
+	file, err := profile.File()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return profile.Load(file)
+}
+
+func formatValues(values map[string]string) string {
+	pairs := make([]string, 0, len(values))
+
+	for k, v := range values {
+		pairs = append(pairs, k+"="+v)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, " ")
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"fmt"
-	s "github.com/baopham/snip/snippet"
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
 	"strings"
@@ -15,13 +14,13 @@ func Remove(c *cli.Context) error {
 		return MissingInfoError{Message: "Please specify the snippet keyword"}
 	}
 
-	filePath, err := s.SnippetFile()
+	library, err := openLibrary(c)
 
 	if err != nil {
 		return err
 	}
 
-	snippet, err := s.SearchExact(keyword, filePath)
+	snippet, err := library.SearchExact(keyword)
 
 	if err != nil {
 		return err
@@ -31,7 +30,7 @@ func Remove(c *cli.Context) error {
 		return NotFoundSnippet{Keyword: keyword}
 	}
 
-	err = snippet.Remove(filePath)
+	err = snippet.Remove(snippet.Source)
 
 	if err != nil {
 		return err
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+)
+
+const (
+	STEP_NOTE    = "note"
+	STEP_RAN     = "ran"
+	STEP_FAILED  = "failed"
+	STEP_SKIPPED = "skipped"
+	STEP_ABORTED = "aborted"
+	STEP_PENDING = "not run"
+)
+
+// stepResult is what happened to a runbook step
+type stepResult struct {
+	step     s.Step
+	command  string
+	status   string
+	exitCode int
+}
+
+func executeRunbook(c *cli.Context, snippet *s.Snippet, mapper map[string]string) error {
+	force := forced(c)
+
+	steps := snippet.Steps()
+	results := make([]*stepResult, len(steps))
+	values := make(map[string]string, len(mapper))
+
+	for k, v := range mapper {
+		values[k] = v
+	}
+
+	for i, step := range steps {
+		results[i] = &stepResult{step: step, command: step.Content, status: STEP_PENDING}
+	}
+
+	aborted := false
+
+	for i, step := range steps {
+		result := results[i]
+
+		if step.Note {
+			result.status = STEP_NOTE
+			color.Cyan(fmt.Sprintf("# %s", step.Content))
+			continue
+		}
+
+		stepSnippet := step.Snippet(snippet)
+
+		if err := promptMissing(stepSnippet, values); err != nil {
+			return err
+		}
+
+		// the command shown in the prompt and the report, the secrets redacted
+		result.command = display(stepSnippet, values)
+
+		if !force {
+			choice, err := promptChoice(fmt.Sprintf("%sStep %d/%d: %s\n[y]es, [s]kip, [a]bort?", profileLabel(c), i+1, len(steps), result.command), "y", "s", "a")
+
+			if err != nil {
+				return err
+			}
+
+			if choice == "s" {
+				result.status = STEP_SKIPPED
+				continue
+			}
+
+			if choice == "a" {
+				result.status = STEP_ABORTED
+				aborted = true
+				break
+			}
+		}
+
+		cmd := exec.Command(shell(), "-c", stepSnippet.Build(values))
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		stepValues := make(map[string]string)
+
+		for _, placeholder := range stepSnippet.Placeholders() {
+			stepValues[placeholder.Name] = values[placeholder.Name]
+		}
+
+		err := run(cmd, stepSnippet, stepValues)
+		result.exitCode = exitCode(err)
+		result.status = STEP_RAN
+
+		if err == nil {
+			continue
+		}
+
+		result.status = STEP_FAILED
+		color.Red(fmt.Sprintf("Step %d failed: %s", i+1, err.Error()))
+
+		if force {
+			aborted = true
+			break
+		}
+
+		choice, err := promptChoice("Continue with the next steps? [y]es, [a]bort", "y", "a")
+
+		if err != nil {
+			return err
+		}
+
+		if choice == "a" {
+			aborted = true
+			break
+		}
+	}
+
+	return runbookReport(snippet, results, aborted)
+}
+
+func runbookReport(snippet *s.Snippet, results []*stepResult, aborted bool) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"#", "Step", "Status", "Exit"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	failed := 0
+
+	for i, result := range results {
+		exit := ""
+
+		if result.status == STEP_RAN || result.status == STEP_FAILED {
+			exit = fmt.Sprint(result.exitCode)
+		}
+
+		if result.status == STEP_FAILED {
+			failed++
+		}
+
+		table.Append([]string{fmt.Sprint(i + 1), result.command, result.status, exit})
+	}
+
+	fmt.Println()
+	table.Render()
+
+	if aborted || failed > 0 {
+		return RunbookError{Keyword: snippet.Keyword, Failed: failed, Aborted: aborted}
+	}
+
+	color.Green(fmt.Sprintf("Runbook '%s' is completed", snippet.Keyword))
+
+	return nil
+}
@@ -1,10 +1,9 @@
 package cli
 
 import (
+	"github.com/baopham/snip/config"
 	s "github.com/baopham/snip/snippet"
-	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli"
-	"os"
 	"strings"
 )
 
@@ -15,31 +14,57 @@ func Search(c *cli.Context) error {
 		return MissingInfoError{Message: "Please specify your keyword"}
 	}
 
-	filePath, err := s.SnippetFile()
+	var snippets []*s.Snippet
+	var err error
+
+	if c.Bool("all-libs") {
+		snippets, err = searchLibraries(searchTerm)
+	} else {
+		snippets, err = searchLibrary(c, searchTerm)
+	}
 
 	if err != nil {
 		return err
 	}
 
-	snippets, err := s.Search(searchTerm, filePath)
+	return renderSnippets(c, visible(c, snippets), searchTerm)
+}
+
+func searchLibrary(c *cli.Context, searchTerm string) ([]*s.Snippet, error) {
+	library, err := openLibrary(c)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Keyword", "Content", "Description"})
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	return library.Search(searchTerm, searchModes[conf.Value(config.SEARCH)])
+}
+
+// searchLibraries searches every named library, the project files are not searched
+func searchLibraries(searchTerm string) ([]*s.Snippet, error) {
+	names, err := s.Libraries()
 
-	for _, snippet := range snippets {
-		table.Append([]string{
-			snippet.Keyword,
-			snippet.Content,
-			snippet.Description,
-		})
+	if err != nil {
+		return nil, err
 	}
 
-	table.Render()
+	var snippets []*s.Snippet
+
+	for _, name := range names {
+		file, err := s.LibraryFile(name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		found, err := s.NewLibrary(file).Search(searchTerm, searchModes[conf.Value(config.SEARCH)])
+
+		if err != nil {
+			return nil, err
+		}
+
+		snippets = append(snippets, found...)
+	}
 
-	return nil
+	return snippets, nil
 }
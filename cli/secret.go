@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baopham/snip/config"
+	"github.com/baopham/snip/secret"
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+// secretRecord is a secret of the vault in the output of snip secret list, never its value
+type secretRecord struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+func (r *secretRecord) header() []string {
+	return []string{"name"}
+}
+
+func (r *secretRecord) values() []string {
+	return []string{r.Name}
+}
+
+func SecretList(c *cli.Context) error {
+	vault, err := openVault()
+
+	if err != nil {
+		return err
+	}
+
+	names, err := vault.Names()
+
+	if err != nil {
+		return err
+	}
+
+	records := make([]item, len(names))
+	rows := make([][]string, len(names))
+
+	for i, name := range names {
+		records[i] = &secretRecord{Name: name}
+		rows[i] = []string{name}
+	}
+
+	return render(c, (&secretRecord{}).header(), records, func() error {
+		return writeTable(c, []tableColumn{{Header: "Name"}}, rows)
+	})
+}
+
+// SecretSet saves a secret in the vault, its value is asked without echo: snip secret set prod/token
+func SecretSet(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" {
+		return MissingInfoError{Message: "Please specify the name of the secret: snip secret set token"}
+	}
+
+	if len(c.Args()) > 1 {
+		return MissingInfoError{Message: "Please do not pass the secret as an argument, it is asked instead: snip secret set " + name}
+	}
+
+	vault, err := openVault()
+
+	if err != nil {
+		return err
+	}
+
+	// unlocks the vault before asking the secret
+	if _, err := vault.Names(); err != nil {
+		return err
+	}
+
+	value, err := promptValue(name, true)
+
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		return MissingInfoError{Message: "Please specify the value of the secret"}
+	}
+
+	if err := vault.Set(name, value); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("Secret '%s' is saved to the vault", name))
+
+	return nil
+}
+
+func SecretDelete(c *cli.Context) error {
+	name := strings.TrimSpace(c.Args().First())
+
+	if name == "" {
+		return MissingInfoError{Message: "Please specify the name of the secret"}
+	}
+
+	vault, err := openVault()
+
+	if err != nil {
+		return err
+	}
+
+	if err := vault.Delete(name); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("Secret '%s' is deleted from the vault", name))
+
+	return nil
+}
+
+// withSecrets fills the secret placeholders which were not given from the providers of the config,
+// trying <profile>/<name> before <name>, and asks the ones they do not have without echo
+func withSecrets(c *cli.Context, snippet *s.Snippet, mapper map[string]string) error {
+	var providers []secret.Provider
+
+	for _, placeholder := range snippet.Placeholders() {
+		if _, ok := mapper[placeholder.Name]; ok || !placeholder.Secret {
+			continue
+		}
+
+		if providers == nil {
+			var err error
+
+			if providers, err = secretProviders(); err != nil {
+				return err
+			}
+		}
+
+		value, ok, err := lookupSecret(providers, secretNames(c, placeholder.Name))
+
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			if value, err = promptValue(placeholder.Name, true); err != nil {
+				return err
+			}
+		}
+
+		mapper[placeholder.Name] = value
+	}
+
+	return nil
+}
+
+func lookupSecret(providers []secret.Provider, names []string) (string, bool, error) {
+	for _, provider := range providers {
+		for _, name := range names {
+			value, ok, err := provider.Lookup(name)
+
+			if err != nil || ok {
+				return value, ok, err
+			}
+		}
+	}
+
+	return "", false, nil
+}
+
+// secretNames returns the names the secret is looked up by, the one of the chosen profile first
+func secretNames(c *cli.Context, name string) []string {
+	if profile := profileName(c); profile != "" {
+		return []string{profile + "/" + name, name}
+	}
+
+	return []string{name}
+}
+
+func secretProviders() ([]secret.Provider, error) {
+	vault, err := openVault()
+
+	if err != nil {
+		return nil, err
+	}
+
+	options := secret.Options{
+		Shell:      shell(),
+		Command:    conf.Value(config.SECRET_COMMAND),
+		PassPrefix: conf.Value(config.PASS_PREFIX),
+		Vault:      vault,
+	}
+
+	var providers []secret.Provider
+
+	for _, name := range strings.Split(conf.Value(config.SECRETS), ",") {
+		provider, err := secret.New(name, options)
+
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+func openVault() (*secret.Vault, error) {
+	file, err := secret.VaultFile()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret.Vault{File: file, Passphrase: Passphrase}, nil
+}
+
+// hasSecrets tells whether the snippet has secret placeholders
+func hasSecrets(snippet *s.Snippet) bool {
+	for _, placeholder := range snippet.Placeholders() {
+		if placeholder.Secret {
+			return true
+		}
+	}
+
+	return false
+}
+
+// display returns the content of the snippet for the messages, the secrets redacted
+func display(snippet *s.Snippet, mapper map[string]string) string {
+	return snippet.Build(redact(snippet, mapper))
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/baopham/snip/clipboard"
+	"github.com/baopham/snip/config"
+	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+// DefaultClearAfter is how long sensitive snippets stay in the clipboard
+const DefaultClearAfter = 30 * time.Second
+
+// clipboardRestore is what the background process needs to restore the clipboard
+type clipboardRestore struct {
+	Previous string        `json:"previous"`
+	Hash     string        `json:"hash"`
+	After    time.Duration `json:"after"`
+}
+
+// writeSensitive saves the content to the clipboard and starts a background process
+// restoring the previous content of the clipboard once the timeout is over.
+// The providers which cannot read the clipboard, e.g. osc52, only get the content with a warning
+func writeSensitive(c *cli.Context, provider clipboard.Provider, snippet *s.Snippet, content string, after time.Duration) error {
+	previous, err := provider.Read()
+
+	if _, unsupported := err.(clipboard.ReadUnsupportedError); unsupported {
+		if werr := provider.Write(content); werr != nil {
+			return werr
+		}
+
+		if provider.Name() != clipboard.STDOUT {
+			color.Yellow(fmt.Sprintf("`%s` has been saved to your clipboard (%s) but it will not be cleared: %s", mask(snippet), provider.Name(), err.Error()))
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return SensitiveClipboardError{Provider: provider.Name(), Err: err}
+	}
+
+	if err = provider.Write(content); err != nil {
+		return err
+	}
+
+	if err = startRestore(c, provider, clipboardRestore{Previous: previous, Hash: hash(content), After: after}); err != nil {
+		return err
+	}
+
+	color.Green(fmt.Sprintf("`%s` has been saved to your clipboard (%s), it will be cleared in %s", mask(snippet), provider.Name(), after))
+
+	return nil
+}
+
+func startRestore(c *cli.Context, provider clipboard.Provider, restore clipboardRestore) error {
+	executable, err := os.Executable()
+
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(restore)
+
+	if err != nil {
+		return err
+	}
+
+	// the provider as it was chosen, e.g. file:/path in the config, the detected one otherwise
+	name := c.GlobalString("clipboard")
+
+	if name == "" {
+		name = conf.Value(config.CLIPBOARD)
+	}
+
+	if name == "" {
+		name = provider.Name()
+	}
+
+	cmd := exec.Command(executable, "--clipboard", name, "restore-clipboard")
+
+	stdin, err := cmd.StdinPipe()
+
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	if _, err = stdin.Write(payload); err != nil {
+		return err
+	}
+
+	// the process keeps running in the background once snip exits
+	return stdin.Close()
+}
+
+// RestoreClipboard waits then restores the previous content of the clipboard,
+// only if it still holds the sensitive snippet
+func RestoreClipboard(c *cli.Context) error {
+	restore := clipboardRestore{}
+
+	if err := json.NewDecoder(os.Stdin).Decode(&restore); err != nil {
+		return err
+	}
+
+	provider, err := getClipboard(c)
+
+	if err != nil {
+		return err
+	}
+
+	time.Sleep(restore.After)
+
+	current, err := provider.Read()
+
+	if err != nil || hash(current) != restore.Hash {
+		return err
+	}
+
+	return provider.Write(restore.Previous)
+}
+
+// mask hides the content of a sensitive snippet in the messages
+func mask(snippet *s.Snippet) string {
+	return snippet.Keyword + ": ********"
+}
+
+func hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
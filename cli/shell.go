@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+const bashInit = `# snip: Ctrl-G picks a snippet and inserts it in the command line
+__snip_widget() {
+  local snippet
+  snippet="$(snip pick --print)" || return
+  READLINE_LINE="${READLINE_LINE:0:$READLINE_POINT}${snippet}${READLINE_LINE:$READLINE_POINT}"
+  READLINE_POINT=$(( READLINE_POINT + ${#snippet} ))
+}
+bind -x '"\C-g": __snip_widget'
+`
+
+const zshInit = `# snip: Ctrl-G picks a snippet and inserts it in the command line
+__snip_widget() {
+  local snippet
+  snippet="$(snip pick --print </dev/tty)"
+  if [[ $? -eq 0 ]]; then
+    LBUFFER="${LBUFFER}${snippet}"
+  fi
+  zle reset-prompt
+}
+zle -N __snip_widget
+bindkey '^G' __snip_widget
+`
+
+const fishInit = `# snip: Ctrl-G picks a snippet and inserts it in the command line
+function __snip_widget
+  set -l snippet (snip pick --print | string collect)
+  and commandline --insert -- $snippet
+  commandline --function repaint
+end
+bind \cg __snip_widget
+`
+
+var shellInits = map[string]string{
+	"bash": bashInit,
+	"zsh":  zshInit,
+	"fish": fishInit,
+}
+
+// ShellInit prints the widget of the shell, e.g. eval "$(snip shell-init bash)"
+func ShellInit(c *cli.Context) error {
+	shell := strings.TrimSpace(c.Args().First())
+
+	if shell == "" {
+		return MissingInfoError{Message: "Please specify your shell: bash, zsh or fish"}
+	}
+
+	script, ok := shellInits[shell]
+
+	if !ok {
+		return UnsupportedShellError{Shell: shell}
+	}
+
+	fmt.Print(script)
+
+	return nil
+}
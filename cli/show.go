@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baopham/snip/highlight"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+func Show(c *cli.Context) error {
+	keyword := strings.TrimSpace(c.Args().First())
+
+	if keyword == "" {
+		return MissingInfoError{Message: "Please specify the snippet keyword"}
+	}
+
+	find := findSnippet
+
+	if c.Bool("raw") {
+		find = findRawSnippet
+	}
+
+	snippet, err := find(c, keyword)
+
+	if err != nil {
+		return err
+	}
+
+	r := newSnippetRecord(snippet)
+	content := highlight.Highlight(r.Content, highlight.Detect(r.Content), nil)
+
+	if snippet.Sensitive && !c.Bool("reveal") {
+		r.Content = "********"
+		content = r.Content
+	}
+
+	return render(c, r.header(), []item{r}, func() error {
+		placeholders := make([]string, 0)
+
+		for _, placeholder := range r.Placeholders {
+			name := placeholder.Name
+
+			if placeholder.Secret {
+				name += " (secret)"
+			}
+
+			placeholders = append(placeholders, name)
+		}
+
+		label := color.New(color.Bold).SprintFunc()
+
+		fmt.Printf("%s %s\n", label("Keyword:     "), r.Keyword)
+		fmt.Printf("%s %s\n", label("Description: "), r.Description)
+		fmt.Printf("%s %s\n", label("Type:        "), r.Type)
+		fmt.Printf("%s %s\n", label("Source:      "), r.Source)
+
+		if r.Sensitive {
+			fmt.Printf("%s %s\n", label("Sensitive:   "), "yes")
+		}
+
+		if snippet.Encrypted {
+			fmt.Printf("%s %s\n", label("Encrypted:   "), "yes")
+		}
+
+		fmt.Printf("%s %s\n", label("Placeholders:"), strings.Join(placeholders, ", "))
+		fmt.Printf("%s\n%s\n", label("Content:"), content)
+
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	s "github.com/baopham/snip/snippet"
+	"github.com/baopham/snip/suggest"
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+)
+
+// Suggest proposes snippets made out of the commands of the shell history, and saves the accepted ones
+func Suggest(c *cli.Context) error {
+	files := c.StringSlice("history")
+
+	if len(files) == 0 {
+		files = suggest.HistoryFiles()
+	}
+
+	if len(files) == 0 {
+		return MissingInfoError{Message: "No shell history found, please specify it with --history"}
+	}
+
+	var commands []string
+
+	for _, file := range files {
+		read, err := suggest.ReadHistory(file)
+
+		if err != nil {
+			return err
+		}
+
+		commands = append(commands, read...)
+	}
+
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return err
+	}
+
+	existing, err := library.GetAll()
+
+	if err != nil {
+		return err
+	}
+
+	suggestions := suggest.Suggest(commands, existing, suggest.Options{
+		MinCount:    c.Int("min-count"),
+		LongCommand: c.Int("long"),
+	})
+
+	if limit := c.Int("limit"); limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	if len(suggestions) == 0 {
+		color.Yellow("No suggestion")
+		return nil
+	}
+
+	if !c.Bool("dry-run") {
+		if err := useTerminal(); err != nil {
+			return err
+		}
+	}
+
+	saved := 0
+
+	for i, suggestion := range suggestions {
+		fmt.Fprintf(os.Stderr, "[%d/%d] ", i+1, len(suggestions))
+		color.New(color.Bold).Fprint(os.Stderr, suggestion.Content)
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintf(os.Stderr, "      run %d times, e.g. %s\n", suggestion.Count, oneLine(suggestion.Examples[0]))
+
+		if c.Bool("dry-run") {
+			continue
+		}
+
+		answer, err := promptChoice("Save? [y]es, [n]o or [q]uit", "yes", "no", "quit")
+
+		if err != nil {
+			return err
+		}
+
+		if answer == "quit" {
+			break
+		}
+
+		if answer == "no" {
+			continue
+		}
+
+		snippet, err := promptSuggestion(suggestion)
+
+		if err != nil {
+			return err
+		}
+
+		if err := snippet.Save(library.Global()); err != nil {
+			color.Red(err.Error())
+			continue
+		}
+
+		saved++
+		color.Green("Saved: " + snippet.Keyword)
+	}
+
+	if !c.Bool("dry-run") {
+		color.Cyan("%d snippets saved", saved)
+	}
+
+	return nil
+}
+
+// promptSuggestion asks the keyword and the description of the accepted suggestion
+func promptSuggestion(suggestion *suggest.Suggestion) (*s.Snippet, error) {
+	snippet := &s.Snippet{Content: suggestion.Content}
+
+	for snippet.Keyword == "" {
+		keyword, err := promptValue("keyword", false)
+
+		if err != nil {
+			return nil, err
+		}
+
+		snippet.Keyword = strings.TrimSpace(keyword)
+	}
+
+	description, err := promptValue("description", false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	snippet.Description = description
+
+	return snippet, nil
+}
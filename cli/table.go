@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/baopham/snip/util"
+	"github.com/mattn/go-runewidth"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+	"golang.org/x/term"
+)
+
+// minColumnWidth is the narrowest a shrunk column gets
+const minColumnWidth = 10
+
+// tableColumn describes how a column fits in the terminal
+type tableColumn struct {
+	Header string
+	// Wrap the text on several lines instead of truncating it to its first line
+	Wrap bool
+	// Fixed columns are never shrunk, e.g. the keyword
+	Fixed bool
+	// Highlight colors the cell of the row once fitted
+	Highlight func(row int, cell string) string
+}
+
+// writeTable renders the rows fitted to the width of the terminal, unless --wide is given,
+// and pages the table through $PAGER when it is taller than the terminal
+func writeTable(c *cli.Context, columns []tableColumn, rows [][]string) error {
+	width, height := terminalSize()
+
+	if !c.Bool("wide") && width > 0 {
+		rows = fitRows(columns, rows, width)
+	}
+
+	rows = highlightRows(columns, rows)
+
+	header := make([]string, len(columns))
+
+	for i, column := range columns {
+		header[i] = column.Header
+	}
+
+	out := &bytes.Buffer{}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(header)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(rows)
+	table.Render()
+
+	if height > 0 && strings.Count(out.String(), "\n") > height {
+		return page(out)
+	}
+
+	_, err := io.Copy(os.Stdout, out)
+
+	return err
+}
+
+// terminalSize returns the size of the terminal stdout writes to, 0 when it is not a terminal.
+// $COLUMNS overrides the width
+func terminalSize() (int, int) {
+	if !util.IsTerminal(os.Stdout) {
+		return 0, 0
+	}
+
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+
+	if err != nil {
+		return 0, 0
+	}
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		width = columns
+	}
+
+	return width, height
+}
+
+// page writes the output through $PAGER, less by default
+func page(out io.Reader) error {
+	pager := strings.TrimSpace(os.Getenv("PAGER"))
+
+	if pager == "" {
+		pager = "less -FRX"
+	}
+
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = out
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// highlightRows colors the cells of the columns having a Highlight function
+func highlightRows(columns []tableColumn, rows [][]string) [][]string {
+	highlighted := make([][]string, len(rows))
+
+	for i, row := range rows {
+		highlighted[i] = make([]string, len(row))
+
+		for j, cell := range row {
+			if columns[j].Highlight != nil {
+				cell = columns[j].Highlight(i, cell)
+			}
+
+			highlighted[i][j] = cell
+		}
+	}
+
+	return highlighted
+}
+
+// fitRows wraps or truncates the cells so that the table fits in the width
+func fitRows(columns []tableColumn, rows [][]string, width int) [][]string {
+	widths := columnWidths(columns, rows, width)
+	fitted := make([][]string, len(rows))
+
+	for i, row := range rows {
+		fitted[i] = make([]string, len(row))
+
+		for j, cell := range row {
+			if columns[j].Wrap {
+				fitted[i][j] = wrap(cell, widths[j])
+			} else {
+				fitted[i][j] = truncate(cell, widths[j])
+			}
+		}
+	}
+
+	return fitted
+}
+
+// columnWidths keeps the natural width of the fixed columns and shares
+// the remaining width between the others, proportionally to their natural width
+func columnWidths(columns []tableColumn, rows [][]string, width int) []int {
+	natural := make([]int, len(columns))
+
+	for i, column := range columns {
+		natural[i] = runewidth.StringWidth(column.Header)
+	}
+
+	for _, row := range rows {
+		for i, cell := range row {
+			for _, line := range strings.Split(cell, "\n") {
+				if w := runewidth.StringWidth(line); w > natural[i] {
+					natural[i] = w
+				}
+			}
+		}
+	}
+
+	// each column has a space on both sides and a border on its right, plus the left border
+	available := width - 3*len(columns) - 1
+	total, flexible := 0, 0
+
+	for i, column := range columns {
+		total += natural[i]
+
+		if column.Fixed {
+			available -= natural[i]
+		} else {
+			flexible += natural[i]
+		}
+	}
+
+	if total <= width-3*len(columns)-1 || flexible == 0 {
+		return natural
+	}
+
+	widths := make([]int, len(columns))
+
+	for i, column := range columns {
+		widths[i] = natural[i]
+
+		if column.Fixed {
+			continue
+		}
+
+		widths[i] = available * natural[i] / flexible
+
+		if widths[i] < minColumnWidth {
+			widths[i] = minColumnWidth
+		}
+
+		if widths[i] > natural[i] {
+			widths[i] = natural[i]
+		}
+	}
+
+	return widths
+}
+
+// truncate keeps the first line of the text, cut to the width with an ellipsis
+func truncate(text string, width int) string {
+	lines := strings.SplitN(text, "\n", 2)
+	line := lines[0]
+
+	if len(lines) > 1 {
+		line += " …"
+	}
+
+	if runewidth.StringWidth(line) <= width {
+		return line
+	}
+
+	return runewidth.Truncate(line, width, "…")
+}
+
+// wrap breaks the text on words so that no line is wider than the width
+func wrap(text string, width int) string {
+	var wrapped []string
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+
+		for _, word := range strings.Fields(paragraph) {
+			for runewidth.StringWidth(word) > width {
+				if line != "" {
+					wrapped = append(wrapped, line)
+					line = ""
+				}
+
+				cut := runewidth.Truncate(word, width, "")
+
+				if cut == "" {
+					cut = string([]rune(word)[:1])
+				}
+				wrapped = append(wrapped, cut)
+				word = word[len(cut):]
+			}
+
+			switch {
+			case word == "":
+			case line == "":
+				line = word
+			case runewidth.StringWidth(line+" "+word) <= width:
+				line += " " + word
+			default:
+				wrapped = append(wrapped, line)
+				line = word
+			}
+		}
+
+		wrapped = append(wrapped, line)
+	}
+
+	return strings.Join(wrapped, "\n")
+}
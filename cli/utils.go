@@ -1,37 +1,164 @@
 package cli
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/baopham/snip/clipboard"
+	"github.com/baopham/snip/config"
 	s "github.com/baopham/snip/snippet"
+	"github.com/fatih/color"
 	"github.com/urfave/cli"
-	"strings"
+	"golang.org/x/term"
+)
+
+var (
+	input = os.Stdin
+	stdin = bufio.NewReader(input)
 )
 
 func getSnippetContent(c *cli.Context) (string, error) {
+	snippet, mapper, err := getSnippet(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	return snippet.Build(mapper), nil
+}
+
+func getSnippet(c *cli.Context) (*s.Snippet, map[string]string, error) {
 	keyword := strings.TrimSpace(c.Args().First())
 
 	if keyword == "" {
-		return "", MissingInfoError{Message: "Please specify your keyword"}
+		return nil, nil, MissingInfoError{Message: "Please specify your keyword"}
 	}
 
-	filePath, err := s.SnippetFile()
+	snippet, err := findSnippet(c, keyword)
 
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
 	mapper := getPlaceholderMapper(c.Args())
 
-	snippet, err := s.SearchExact(keyword, filePath)
+	if err := withProfile(c, snippet, mapper); err != nil {
+		return nil, nil, err
+	}
+
+	if err := withSecrets(c, snippet, mapper); err != nil {
+		return nil, nil, err
+	}
+
+	return snippet, mapper, nil
+}
+
+// libraryFlag returns the library chosen with --lib after the command or before it
+func libraryFlag(c *cli.Context) string {
+	if name := strings.TrimSpace(c.String("lib")); name != "" {
+		return name
+	}
+
+	return strings.TrimSpace(c.GlobalString("lib"))
+}
+
+// libraryName returns the library chosen with --lib, the library of the config otherwise
+func libraryName(c *cli.Context) string {
+	if name := libraryFlag(c); name != "" {
+		return name
+	}
+
+	return conf.Value(config.LIBRARY)
+}
+
+// libraryFile returns the snippet file chosen with --file after the command or before it
+func libraryFile(c *cli.Context) string {
+	if file := strings.TrimSpace(c.String("file")); file != "" {
+		return file
+	}
+
+	return strings.TrimSpace(c.GlobalString("file"))
+}
+
+// openLibrary opens the library of the current directory with the library chosen with --lib or the file chosen with --file
+func openLibrary(c *cli.Context) (*s.Library, error) {
+	file := libraryFile(c)
+
+	var (
+		library *s.Library
+		err     error
+	)
+
+	switch {
+	case file == "":
+		library, err = s.OpenLibrary(libraryName(c))
+	case libraryFlag(c) != "":
+		return nil, MissingInfoError{Message: "Please specify either --lib or --file"}
+	default:
+		library, err = s.OpenLibraryFile(file)
+	}
 
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	openedLibrary = library
+
+	return library, nil
+}
+
+// findSnippet finds the snippet by keyword with its includes expanded
+func findSnippet(c *cli.Context, keyword string) (*s.Snippet, error) {
+	snippet, err := findRawSnippet(c, keyword)
+
+	if err != nil {
+		return nil, err
+	}
+
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snippet.Expand(library)
+}
+
+// findRawSnippet finds the snippet by keyword as it was saved
+func findRawSnippet(c *cli.Context, keyword string) (*s.Snippet, error) {
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	snippet, err := library.SearchExact(keyword)
+
+	if err != nil {
+		return nil, err
 	}
 
 	if snippet == nil {
-		return "", NotFoundSnippet{Keyword: keyword}
+		return nil, NotFoundSnippet{Keyword: keyword}
 	}
 
-	return snippet.Build(mapper), nil
+	// a project file can replace a global snippet, e.g. in a checkout which is not trusted
+	if snippet.Shadows != "" {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Snippet %s of %s shadows the one of %s\n", keyword, snippet.Source, snippet.Shadows)
+	}
+
+	return snippet, nil
+}
+
+// getClipboard returns the clipboard provider chosen with --clipboard or in the config, detected otherwise
+func getClipboard(c *cli.Context) (clipboard.Provider, error) {
+	if name := c.GlobalString("clipboard"); name != "" {
+		return clipboard.New(name)
+	}
+
+	return clipboard.New(conf.Value(config.CLIPBOARD))
 }
 
 func getPlaceholderMapper(args cli.Args) map[string]string {
@@ -39,10 +166,114 @@ func getPlaceholderMapper(args cli.Args) map[string]string {
 	pair := args.Get(1)
 
 	for i := 2; pair != ""; i++ {
-		parts := strings.Split(pair, "=")
-		mapper[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		parts := strings.SplitN(pair, "=", 2)
+
+		if len(parts) == 2 {
+			mapper[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+
 		pair = args.Get(i)
 	}
 
 	return mapper
 }
+
+// getPlaceholderValues reads the placeholder values like getPlaceholderMapper
+// but splits the comma separated values of snip x --matrix, e.g. p=8000,8001. Use \, for a literal comma
+func getPlaceholderValues(args cli.Args) map[string][]string {
+	values := make(map[string][]string)
+
+	for k, v := range getPlaceholderMapper(args) {
+		var list []string
+		var current strings.Builder
+
+		for i := 0; i < len(v); i++ {
+			switch {
+			case v[i] == '\\' && i+1 < len(v) && v[i+1] == ',':
+				current.WriteByte(',')
+				i++
+			case v[i] == ',':
+				list = append(list, strings.TrimSpace(current.String()))
+				current.Reset()
+			default:
+				current.WriteByte(v[i])
+			}
+		}
+
+		values[k] = append(list, strings.TrimSpace(current.String()))
+	}
+
+	return values
+}
+
+// useTerminal reads the answers to the prompts from the terminal, for when stdin is not one
+func useTerminal() error {
+	tty, err := os.Open("/dev/tty")
+
+	if err != nil {
+		return err
+	}
+
+	input = tty
+	stdin = bufio.NewReader(tty)
+
+	return nil
+}
+
+// promptMissing asks the values of the placeholders which were not given
+func promptMissing(snippet *s.Snippet, mapper map[string]string) error {
+	for _, placeholder := range snippet.Placeholders() {
+		if _, ok := mapper[placeholder.Name]; ok {
+			continue
+		}
+
+		value, err := promptValue(placeholder.Name, placeholder.Secret)
+
+		if err != nil {
+			return err
+		}
+
+		mapper[placeholder.Name] = value
+	}
+
+	return nil
+}
+
+func promptValue(name string, secret bool) (string, error) {
+	fmt.Fprintf(os.Stderr, "%s: ", name)
+
+	if secret && term.IsTerminal(int(input.Fd())) {
+		value, err := term.ReadPassword(int(input.Fd()))
+		fmt.Fprintln(os.Stderr)
+		return string(value), err
+	}
+
+	value, err := stdin.ReadString('\n')
+
+	if err != nil && value == "" {
+		return "", err
+	}
+
+	return strings.TrimSpace(value), nil
+}
+
+// promptChoice asks the message until one of the choices is answered
+func promptChoice(message string, choices ...string) (string, error) {
+	for {
+		fmt.Fprintf(os.Stderr, "%s ", message)
+
+		answer, err := stdin.ReadString('\n')
+
+		if err != nil && answer == "" {
+			return "", err
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+
+		for _, choice := range choices {
+			if answer != "" && strings.HasPrefix(choice, answer[:1]) {
+				return choice, nil
+			}
+		}
+	}
+}
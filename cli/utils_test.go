@@ -0,0 +1,23 @@
+package cli
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/urfave/cli"
+)
+
+var _ = Describe("Utils", func() {
+	Context("when calling getPlaceholderValues()", func() {
+		It("should split the comma separated values", func() {
+			values := getPlaceholderValues(cli.Args{"port", "p=8000, 8001", "host=localhost"})
+
+			Expect(values).To(Equal(map[string][]string{"p": {"8000", "8001"}, "host": {"localhost"}}))
+		})
+
+		It("should keep the escaped commas", func() {
+			values := getPlaceholderValues(cli.Args{"say", `msg=hello\, world,bye`})
+
+			Expect(values).To(Equal(map[string][]string{"msg": {"hello, world", "bye"}}))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	s "github.com/baopham/snip/snippet"
+	"github.com/urfave/cli"
+)
+
+// whichRecord is a snippet generating the command, with the values of its placeholders
+type whichRecord struct {
+	Keyword      string            `json:"keyword" yaml:"keyword"`
+	Placeholders map[string]string `json:"placeholders" yaml:"placeholders"`
+	Invocation   string            `json:"invocation" yaml:"invocation"`
+	// literal is the length of the content without the placeholders, the more the better the match
+	literal int
+}
+
+func (r *whichRecord) header() []string {
+	return []string{"keyword", "placeholders", "invocation"}
+}
+
+func (r *whichRecord) values() []string {
+	return []string{r.Keyword, r.placeholderValues(), r.Invocation}
+}
+
+func (r *whichRecord) placeholderValues() string {
+	names := make([]string, 0, len(r.Placeholders))
+
+	for name := range r.Placeholders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	values := make([]string, len(names))
+
+	for i, name := range names {
+		values[i] = name + "=" + shellQuote(r.Placeholders[name])
+	}
+
+	return strings.Join(values, " ")
+}
+
+// Which finds the snippets which generate the command, e.g. snip which "lsof -i :8000" gives snip x port p=8000
+func Which(c *cli.Context) error {
+	command := strings.TrimSpace(strings.Join(c.Args(), " "))
+
+	if command == "" {
+		return MissingInfoError{Message: "Please specify the command"}
+	}
+
+	library, err := openLibrary(c)
+
+	if err != nil {
+		return err
+	}
+
+	snippets, err := library.GetAll()
+
+	if err != nil {
+		return err
+	}
+
+	var records []*whichRecord
+
+	for _, snippet := range visible(c, snippets) {
+		if snippet.Type == s.TYPE_RUNBOOK {
+			continue
+		}
+
+		expanded, err := snippet.Expand(library)
+
+		if err != nil {
+			continue
+		}
+
+		values, ok := expanded.Match(command)
+
+		if !ok {
+			continue
+		}
+
+		r := &whichRecord{Keyword: snippet.Keyword, Placeholders: values, literal: expanded.Literal()}
+
+		r.Invocation = strings.TrimSpace("snip x " + shellQuote(snippet.Keyword) + " " + r.placeholderValues())
+		records = append(records, r)
+	}
+
+	if len(records) == 0 {
+		return NoMatchingSnippetError{Command: command}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].literal > records[j].literal
+	})
+
+	items := make([]item, len(records))
+
+	for i, r := range records {
+		items[i] = r
+	}
+
+	return render(c, (&whichRecord{}).header(), items, func() error {
+		columns := []tableColumn{{Header: "Keyword", Fixed: true}, {Header: "Placeholders", Wrap: true}, {Header: "Invocation"}}
+		rows := make([][]string, len(records))
+
+		for i, r := range records {
+			rows[i] = r.values()
+		}
+
+		return writeTable(c, columns, rows)
+	})
+}
+
+// shellQuote quotes the value when the shell would split or expand it
+func shellQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\"\\$`|&;<>()*?[]{}~#!") {
+		return value
+	}
+
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,107 @@
+package clipboard
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"strings"
+
+	system "github.com/atotto/clipboard"
+	"github.com/baopham/snip/snippet"
+	"golang.org/x/term"
+)
+
+const (
+	SYSTEM = "system"
+	OSC52  = "osc52"
+	TMUX   = "tmux"
+	STDOUT = "stdout"
+	FILE   = "file"
+)
+
+// Providers lists the names of the clipboard providers
+var Providers = []string{SYSTEM, OSC52, TMUX, STDOUT, FILE}
+
+// Provider writes to and reads from a clipboard
+type Provider interface {
+	Name() string
+	Write(content string) error
+	Read() (string, error)
+}
+
+// Environment is what the detection of the clipboard provider looks at
+type Environment struct {
+	Getenv          func(string) string
+	GOOS            string
+	Terminal        bool
+	SystemSupported bool
+}
+
+// CurrentEnvironment returns the environment snip is running in
+func CurrentEnvironment() Environment {
+	return Environment{
+		Getenv:          os.Getenv,
+		GOOS:            runtime.GOOS,
+		Terminal:        term.IsTerminal(int(os.Stderr.Fd())),
+		SystemSupported: !system.Unsupported,
+	}
+}
+
+// Detect returns the name of the clipboard provider best suited to the environment
+func Detect(env Environment) string {
+	ssh := env.Getenv("SSH_TTY") != "" || env.Getenv("SSH_CONNECTION") != ""
+
+	// the system clipboard of a remote box is not the one of the user
+	if ssh && env.Terminal {
+		return OSC52
+	}
+
+	if env.Getenv("TMUX") != "" {
+		return TMUX
+	}
+
+	display := env.GOOS != "linux" || env.Getenv("DISPLAY") != "" || env.Getenv("WAYLAND_DISPLAY") != ""
+
+	if env.SystemSupported && display {
+		return SYSTEM
+	}
+
+	if env.Terminal {
+		return OSC52
+	}
+
+	return STDOUT
+}
+
+// New returns the clipboard provider by name. An empty name detects the provider,
+// file:/some/path writes to the given file, file alone to clipboard in the snippet directory
+func New(name string) (Provider, error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		name = Detect(CurrentEnvironment())
+	}
+
+	switch {
+	case name == SYSTEM:
+		return System{}, nil
+	case name == OSC52:
+		return OSC52Terminal{Tmux: os.Getenv("TMUX") != ""}, nil
+	case name == TMUX:
+		return TmuxBuffer{}, nil
+	case name == STDOUT:
+		return Stdout{Out: os.Stdout}, nil
+	case name == FILE:
+		dir, err := snippet.SnippetDir()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return File{Path: path.Join(dir, "clipboard")}, nil
+	case strings.HasPrefix(name, FILE+":"):
+		return File{Path: strings.TrimPrefix(name, FILE+":")}, nil
+	}
+
+	return nil, UnknownProviderError{Name: name}
+}
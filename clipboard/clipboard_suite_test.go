@@ -0,0 +1,13 @@
+package clipboard_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestClipboard(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Clipboard Suite")
+}
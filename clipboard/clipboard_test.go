@@ -0,0 +1,97 @@
+package clipboard_test
+
+import (
+	"bytes"
+	. "github.com/baopham/snip/clipboard"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"os"
+	"path"
+)
+
+var _ = Describe("Clipboard", func() {
+	environment := func(vars map[string]string, terminal, systemSupported bool) Environment {
+		return Environment{
+			Getenv:          func(key string) string { return vars[key] },
+			GOOS:            "linux",
+			Terminal:        terminal,
+			SystemSupported: systemSupported,
+		}
+	}
+
+	Context("when calling Detect()", func() {
+		It("should use the system clipboard on a desktop", func() {
+			Expect(Detect(environment(map[string]string{"DISPLAY": ":0"}, true, true))).To(Equal(SYSTEM))
+		})
+
+		It("should use OSC 52 over SSH", func() {
+			vars := map[string]string{"DISPLAY": ":0", "SSH_TTY": "/dev/pts/1", "TMUX": "/tmp/tmux"}
+			Expect(Detect(environment(vars, true, true))).To(Equal(OSC52))
+		})
+
+		It("should use the tmux buffer inside tmux", func() {
+			Expect(Detect(environment(map[string]string{"TMUX": "/tmp/tmux"}, true, false))).To(Equal(TMUX))
+		})
+
+		It("should use OSC 52 on a headless box with a terminal", func() {
+			Expect(Detect(environment(map[string]string{}, true, true))).To(Equal(OSC52))
+		})
+
+		It("should print to stdout without a terminal", func() {
+			Expect(Detect(environment(map[string]string{}, false, false))).To(Equal(STDOUT))
+		})
+	})
+
+	Context("when calling New()", func() {
+		It("should return the provider by name", func() {
+			provider, err := New("file:/tmp/clip")
+
+			Expect(err).To(BeNil())
+			Expect(provider).To(Equal(File{Path: "/tmp/clip"}))
+
+			_, err = New("nope")
+
+			Expect(err).To(MatchError(UnknownProviderError{Name: "nope"}))
+		})
+	})
+
+	Context("when writing with the OSC 52 provider", func() {
+		It("should write the escape sequence", func() {
+			out := &bytes.Buffer{}
+
+			Expect(OSC52Terminal{Out: out}.Write("hello")).To(Succeed())
+			Expect(out.String()).To(Equal("\x1b]52;c;aGVsbG8=\x07"))
+
+			By("wrapping it for tmux")
+
+			out.Reset()
+
+			Expect(OSC52Terminal{Out: out, Tmux: true}.Write("hello")).To(Succeed())
+			Expect(out.String()).To(Equal("\x1bPtmux;\x1b\x1b]52;c;aGVsbG8=\x07\x1b\\"))
+		})
+	})
+
+	Context("when writing with the file provider", func() {
+		It("should save the content to the file", func() {
+			dir, err := os.Getwd()
+			Expect(err).To(BeNil())
+
+			provider := File{Path: path.Join(dir, ".clipboard")}
+			defer os.Remove(provider.Path)
+
+			Expect(provider.Write("lsof -i :9000")).To(Succeed())
+			Expect(provider.Read()).To(Equal("lsof -i :9000"))
+		})
+	})
+
+	Context("when using the fake provider", func() {
+		It("should remember the writes", func() {
+			fake := &Fake{}
+
+			Expect(fake.Write("a")).To(Succeed())
+			Expect(fake.Write("b")).To(Succeed())
+			Expect(fake.Read()).To(Equal("b"))
+			Expect(fake.Writes).To(Equal([]string{"a", "b"}))
+		})
+	})
+})
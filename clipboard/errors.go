@@ -0,0 +1,24 @@
+package clipboard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownProviderError error when the clipboard provider does not exist
+type UnknownProviderError struct {
+	Name string
+}
+
+func (e UnknownProviderError) Error() string {
+	return fmt.Sprintf("Unknown clipboard provider %s, use one of: %s", e.Name, strings.Join(Providers, ", "))
+}
+
+// ReadUnsupportedError error when the clipboard provider cannot read the clipboard
+type ReadUnsupportedError struct {
+	Name string
+}
+
+func (e ReadUnsupportedError) Error() string {
+	return fmt.Sprintf("The %s clipboard provider cannot read the clipboard", e.Name)
+}
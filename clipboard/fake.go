@@ -0,0 +1,23 @@
+package clipboard
+
+// Fake is an in memory clipboard for tests
+type Fake struct {
+	Content string
+	Writes  []string
+	Err     error
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) Write(content string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.Content = content
+	f.Writes = append(f.Writes, content)
+
+	return nil
+}
+
+func (f *Fake) Read() (string, error) { return f.Content, f.Err }
@@ -0,0 +1,113 @@
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	system "github.com/atotto/clipboard"
+	"github.com/baopham/snip/snippet"
+	"github.com/baopham/snip/util"
+)
+
+// System is the clipboard of the desktop: pbcopy, xclip, xsel, wl-copy, etc.
+type System struct{}
+
+func (System) Name() string { return SYSTEM }
+
+func (System) Write(content string) error { return system.WriteAll(content) }
+
+func (System) Read() (string, error) { return system.ReadAll() }
+
+// OSC52Terminal asks the terminal to set its clipboard with the OSC 52 escape sequence,
+// which also works over SSH
+type OSC52Terminal struct {
+	// Out defaults to the controlling terminal
+	Out io.Writer
+	// Tmux wraps the sequence so that tmux passes it through to the terminal
+	Tmux bool
+}
+
+func (o OSC52Terminal) Name() string { return OSC52 }
+
+func (o OSC52Terminal) Write(content string) error {
+	sequence := fmt.Sprintf("\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(content)))
+
+	if o.Tmux {
+		sequence = fmt.Sprintf("\x1bPtmux;%s\x1b\\", strings.Replace(sequence, "\x1b", "\x1b\x1b", -1))
+	}
+
+	out := o.Out
+
+	if out == nil {
+		tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+
+		if err != nil {
+			out = os.Stderr
+		} else {
+			defer util.Check(tty.Close)
+			out = tty
+		}
+	}
+
+	_, err := io.WriteString(out, sequence)
+
+	return err
+}
+
+func (o OSC52Terminal) Read() (string, error) { return "", ReadUnsupportedError{Name: OSC52} }
+
+// TmuxBuffer is the paste buffer of tmux
+type TmuxBuffer struct{}
+
+func (TmuxBuffer) Name() string { return TMUX }
+
+func (TmuxBuffer) Write(content string) error {
+	cmd := exec.Command("tmux", "load-buffer", "-")
+	cmd.Stdin = strings.NewReader(content)
+	return cmd.Run()
+}
+
+func (TmuxBuffer) Read() (string, error) {
+	out, err := exec.Command("tmux", "save-buffer", "-").Output()
+	return string(out), err
+}
+
+// Stdout prints the content instead of saving it
+type Stdout struct {
+	Out io.Writer
+}
+
+func (Stdout) Name() string { return STDOUT }
+
+func (s Stdout) Write(content string) error {
+	_, err := fmt.Fprintln(s.Out, content)
+	return err
+}
+
+func (Stdout) Read() (string, error) { return "", ReadUnsupportedError{Name: STDOUT} }
+
+// File saves the content to a file
+type File struct {
+	Path string
+}
+
+func (File) Name() string { return FILE }
+
+func (f File) Write(content string) error {
+	return ioutil.WriteFile(f.Path, []byte(content), snippet.FileMode)
+}
+
+func (f File) Read() (string, error) {
+	b, err := ioutil.ReadFile(f.Path)
+
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return string(b), err
+}
@@ -0,0 +1,434 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/baopham/snip/agent"
+	"github.com/baopham/snip/clipboard"
+	"github.com/baopham/snip/highlight"
+	"github.com/baopham/snip/profile"
+	"github.com/baopham/snip/secret"
+	"github.com/baopham/snip/snippet"
+)
+
+// the settings of the config file
+const (
+	SHELL     = "shell"
+	CLIPBOARD = "clipboard"
+	EDITOR    = "editor"
+	SEARCH    = "search"
+	CONFIRM   = "confirm"
+	FORMAT    = "format"
+	COLOR     = "color"
+	THEME     = "theme"
+	LIBRARY   = "library"
+	PROFILE   = "profile"
+
+	SECRETS        = "secrets"
+	SECRET_COMMAND = "secret_command"
+	PASS_PREFIX    = "pass_prefix"
+	UNLOCK_TIMEOUT = "unlock_timeout"
+)
+
+// the values of the search setting
+const (
+	SEARCH_FUZZY     = "fuzzy"
+	SEARCH_SUBSTRING = "substring"
+	SEARCH_EXACT     = "exact"
+)
+
+// the values of the confirm setting
+const (
+	CONFIRM_ALWAYS = "always"
+	CONFIRM_NEVER  = "never"
+)
+
+// the values of the color setting
+const (
+	COLOR_AUTO   = "auto"
+	COLOR_ALWAYS = "always"
+	COLOR_NEVER  = "never"
+)
+
+// FILE_ENV is the environment variable overriding the path of the config file
+const FILE_ENV = "SNIP_CONFIG"
+
+// Config is the content of the config file, an empty value is the default of the setting
+type Config struct {
+	Shell     string `toml:"shell,omitempty"`
+	Clipboard string `toml:"clipboard,omitempty"`
+	Editor    string `toml:"editor,omitempty"`
+	Search    string `toml:"search,omitempty"`
+	Confirm   string `toml:"confirm,omitempty"`
+	Format    string `toml:"format,omitempty"`
+	Color     string `toml:"color,omitempty"`
+	Theme     string `toml:"theme,omitempty"`
+	Library   string `toml:"library,omitempty"`
+	Profile   string `toml:"profile,omitempty"`
+
+	Secrets       string `toml:"secrets,omitempty"`
+	SecretCommand string `toml:"secret_command,omitempty"`
+	PassPrefix    string `toml:"pass_prefix,omitempty"`
+	UnlockTimeout string `toml:"unlock_timeout,omitempty"`
+}
+
+// Setting describes a setting of the config file
+type Setting struct {
+	Key   string
+	Usage string
+	// Env is the environment variable overriding the config file
+	Env string
+	// Values lists the accepted values, any value is accepted when empty
+	Values []string
+	// Default returns the value used when the setting is not set
+	Default func() string
+	field   func(c *Config) *string
+}
+
+// Settings lists the settings of the config file
+var Settings = []*Setting{
+	{
+		Key:     SHELL,
+		Usage:   "shell executing the snippets: bash, zsh, sh, fish or the absolute path of a shell",
+		Env:     "SNIP_SHELL",
+		Default: constant("bash"),
+		field:   func(c *Config) *string { return &c.Shell },
+	},
+	{
+		Key:     CLIPBOARD,
+		Usage:   "clipboard provider: system, osc52, tmux, stdout, file or file:/some/path, detected when empty",
+		Env:     "SNIP_CLIPBOARD",
+		Default: constant(""),
+		field:   func(c *Config) *string { return &c.Clipboard },
+	},
+	{
+		Key:     EDITOR,
+		Usage:   "editor opening the config file, $VISUAL or $EDITOR by default",
+		Env:     "SNIP_EDITOR",
+		Default: defaultEditor,
+		field:   func(c *Config) *string { return &c.Editor },
+	},
+	{
+		Key:     SEARCH,
+		Usage:   "how snip search matches the snippets",
+		Env:     "SNIP_SEARCH",
+		Values:  []string{SEARCH_FUZZY, SEARCH_SUBSTRING, SEARCH_EXACT},
+		Default: constant(SEARCH_FUZZY),
+		field:   func(c *Config) *string { return &c.Search },
+	},
+	{
+		Key:     CONFIRM,
+		Usage:   "whether to ask before executing a snippet or saving changes, never is like --force",
+		Env:     "SNIP_CONFIRM",
+		Values:  []string{CONFIRM_ALWAYS, CONFIRM_NEVER},
+		Default: constant(CONFIRM_ALWAYS),
+		field:   func(c *Config) *string { return &c.Confirm },
+	},
+	{
+		Key:     FORMAT,
+		Usage:   "output format of the read commands when --format is not given",
+		Env:     "SNIP_FORMAT",
+		Values:  []string{"table", "json", "yaml", "csv", "tsv"},
+		Default: constant("table"),
+		field:   func(c *Config) *string { return &c.Format },
+	},
+	{
+		Key:     COLOR,
+		Usage:   "colored output, auto disables it when stdout is not a terminal or NO_COLOR is set",
+		Env:     "SNIP_COLOR",
+		Values:  []string{COLOR_AUTO, COLOR_ALWAYS, COLOR_NEVER},
+		Default: constant(COLOR_AUTO),
+		field:   func(c *Config) *string { return &c.Color },
+	},
+	{
+		Key:     THEME,
+		Usage:   "colors of the highlighted snippets",
+		Env:     "SNIP_THEME",
+		Values:  highlight.Themes,
+		Default: constant(highlight.DEFAULT_THEME),
+		field:   func(c *Config) *string { return &c.Theme },
+	},
+	{
+		Key:     LIBRARY,
+		Usage:   "library used when --lib is not given",
+		Env:     "SNIP_LIB",
+		Default: constant(snippet.DEFAULT_LIBRARY),
+		field:   func(c *Config) *string { return &c.Library },
+	},
+	{
+		Key:     PROFILE,
+		Usage:   "active profile filling the placeholders when --profile is not given, none when empty",
+		Env:     "SNIP_PROFILE",
+		Default: constant(""),
+		field:   func(c *Config) *string { return &c.Profile },
+	},
+	{
+		Key:     SECRETS,
+		Usage:   "comma separated providers of the {name:secret} placeholders tried in order: env, vault, pass, command, prompted otherwise",
+		Env:     "SNIP_SECRETS",
+		Default: constant(secret.ENV),
+		field:   func(c *Config) *string { return &c.Secrets },
+	},
+	{
+		Key:     SECRET_COMMAND,
+		Usage:   "command printing a secret for the command provider, {name} is the name of the secret",
+		Env:     "SNIP_SECRET_COMMAND",
+		Default: constant(""),
+		field:   func(c *Config) *string { return &c.SecretCommand },
+	},
+	{
+		Key:     PASS_PREFIX,
+		Usage:   "directory of the secrets in the pass password store",
+		Env:     "SNIP_PASS_PREFIX",
+		Default: constant("snip"),
+		field:   func(c *Config) *string { return &c.PassPrefix },
+	},
+	{
+		Key:     UNLOCK_TIMEOUT,
+		Usage:   "how long the passphrase of the encrypted snippets and the vault is kept by the agent, 0 to ask it every time",
+		Env:     "SNIP_UNLOCK_TIMEOUT",
+		Default: constant(agent.DEFAULT_TIMEOUT.String()),
+		field:   func(c *Config) *string { return &c.UnlockTimeout },
+	},
+}
+
+// Keys returns the keys of the settings
+func Keys() []string {
+	keys := make([]string, len(Settings))
+
+	for i, setting := range Settings {
+		keys[i] = setting.Key
+	}
+
+	return keys
+}
+
+// Lookup returns the setting by key
+func Lookup(key string) (*Setting, error) {
+	for _, setting := range Settings {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+
+	return nil, UnknownKeyError{Key: key}
+}
+
+// File returns the path of the config file: $SNIP_CONFIG if set, $XDG_CONFIG_HOME/snip/config.toml otherwise,
+// ~/.config/snip/config.toml by default
+func File() (string, error) {
+	if file := os.Getenv(FILE_ENV); file != "" {
+		return filepath.Abs(file)
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "snip", "config.toml"), nil
+	}
+
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config", "snip", "config.toml"), nil
+}
+
+// Read returns the settings of the config file without validating them, empty when there is no file
+func Read(file string) (*Config, error) {
+	c := &Config{}
+	content, err := ioutil.ReadFile(file)
+
+	// nor is there any in a home directory which cannot be used, e.g. HOME=/dev/null
+	if os.IsNotExist(err) || errors.Is(err, syscall.ENOTDIR) {
+		return c, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := toml.Decode(string(content), c)
+
+	if err != nil {
+		return nil, FileError{File: file, Err: err}
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, FileError{File: file, Err: UnknownKeyError{Key: undecoded[0].String()}}
+	}
+
+	return c, nil
+}
+
+// Load returns the validated settings of the config file overridden by the environment variables
+func Load(file string) (*Config, error) {
+	c, err := Read(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, FileError{File: file, Err: err}
+	}
+
+	for _, setting := range Settings {
+		if value, ok := os.LookupEnv(setting.Env); ok && value != "" {
+			if err := setting.validate(value); err != nil {
+				return nil, EnvError{Env: setting.Env, Err: err}
+			}
+
+			*setting.field(c) = value
+		}
+	}
+
+	return c, nil
+}
+
+// Save writes the settings which are set to the config file
+func (c *Config) Save(file string) error {
+	var buffer bytes.Buffer
+
+	if err := toml.NewEncoder(&buffer).Encode(c); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, buffer.Bytes(), 0644)
+}
+
+// Get returns the value of the setting, its default when not set
+func (c *Config) Get(key string) (string, error) {
+	setting, err := Lookup(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	if value := *setting.field(c); value != "" {
+		return value, nil
+	}
+
+	return setting.Default(), nil
+}
+
+// Value returns the value of the setting like Get, for the keys known to be valid
+func (c *Config) Value(key string) string {
+	value, _ := c.Get(key)
+	return value
+}
+
+// IsSet tells whether the setting is set, by the config file or the environment
+func (c *Config) IsSet(key string) bool {
+	setting, err := Lookup(key)
+	return err == nil && *setting.field(c) != ""
+}
+
+// Set validates and changes the setting, an empty value unsets it
+func (c *Config) Set(key, value string) error {
+	setting, err := Lookup(key)
+
+	if err != nil {
+		return err
+	}
+
+	value = strings.TrimSpace(value)
+
+	if value != "" {
+		if err := setting.validate(value); err != nil {
+			return err
+		}
+	}
+
+	*setting.field(c) = value
+
+	return nil
+}
+
+// Validate checks the settings which are set
+func (c *Config) Validate() error {
+	for _, setting := range Settings {
+		if value := *setting.field(c); value != "" {
+			if err := setting.validate(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Setting) validate(value string) error {
+	switch s.Key {
+	case SHELL:
+		if !filepath.IsAbs(value) && !contains([]string{"bash", "zsh", "sh", "fish"}, value) {
+			return InvalidValueError{Key: s.Key, Value: value, Usage: "bash, zsh, sh, fish or the absolute path of a shell"}
+		}
+	case CLIPBOARD:
+		if !contains(clipboard.Providers, value) && !strings.HasPrefix(value, clipboard.FILE+":") {
+			return InvalidValueError{Key: s.Key, Value: value, Values: clipboard.Providers}
+		}
+	case LIBRARY:
+		if _, err := snippet.LibraryFile(value); err != nil {
+			return InvalidValueError{Key: s.Key, Value: value, Usage: "letters, digits, - and _, see snip lib list"}
+		}
+	case SECRETS:
+		for _, name := range strings.Split(value, ",") {
+			if !contains(secret.Providers, strings.TrimSpace(name)) {
+				return InvalidValueError{Key: s.Key, Value: value, Usage: "a comma separated list of " + strings.Join(secret.Providers, ", ")}
+			}
+		}
+	case UNLOCK_TIMEOUT:
+		if timeout, err := time.ParseDuration(value); err != nil || timeout < 0 {
+			return InvalidValueError{Key: s.Key, Value: value, Usage: "a duration, e.g. 15m or 1h, 0 to disable"}
+		}
+	case PROFILE:
+		if err := profile.CheckName(value); err != nil {
+			return InvalidValueError{Key: s.Key, Value: value, Usage: "letters, digits, - and _, see snip profile list"}
+		}
+	}
+
+	if len(s.Values) > 0 && !contains(s.Values, value) {
+		return InvalidValueError{Key: s.Key, Value: value, Values: s.Values}
+	}
+
+	return nil
+}
+
+func defaultEditor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor
+		}
+	}
+
+	return "vi"
+}
+
+func constant(value string) func() string {
+	return func() string {
+		return value
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/baopham/snip/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var dir, file string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-config")
+		Expect(err).To(BeNil())
+
+		file = filepath.Join(dir, "snip", "config.toml")
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("SNIP_SEARCH")
+		os.RemoveAll(dir)
+	})
+
+	writeConfig := func(content string) {
+		Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(BeNil())
+		Expect(ioutil.WriteFile(file, []byte(content), 0644)).To(BeNil())
+	}
+
+	Context("when calling Load()", func() {
+		It("should return the defaults without a config file", func() {
+			c, err := Load(file)
+
+			Expect(err).To(BeNil())
+			Expect(c.Value(SHELL)).To(Equal("bash"))
+			Expect(c.Value(SEARCH)).To(Equal(SEARCH_FUZZY))
+			Expect(c.Value(CONFIRM)).To(Equal(CONFIRM_ALWAYS))
+			Expect(c.Value(LIBRARY)).To(Equal("default"))
+			Expect(c.IsSet(SHELL)).To(BeFalse())
+		})
+
+		It("should read the config file overridden by the environment", func() {
+			writeConfig("shell = \"zsh\"\nsearch = \"exact\"\nformat = \"json\"\n")
+			os.Setenv("SNIP_SEARCH", "substring")
+
+			c, err := Load(file)
+
+			Expect(err).To(BeNil())
+			Expect(c.Value(SHELL)).To(Equal("zsh"))
+			Expect(c.Value(FORMAT)).To(Equal("json"))
+			Expect(c.Value(SEARCH)).To(Equal(SEARCH_SUBSTRING))
+		})
+
+		It("should reject the unknown settings and the invalid values", func() {
+			writeConfig("shel = \"zsh\"\n")
+
+			_, err := Load(file)
+			Expect(err).To(Equal(FileError{File: file, Err: UnknownKeyError{Key: "shel"}}))
+
+			writeConfig("confirm = \"sometimes\"\n")
+
+			_, err = Load(file)
+			Expect(err).To(Equal(FileError{File: file, Err: InvalidValueError{Key: CONFIRM, Value: "sometimes", Values: []string{CONFIRM_ALWAYS, CONFIRM_NEVER}}}))
+
+			writeConfig("confirm = \"never\"\n")
+			os.Setenv("SNIP_SEARCH", "regex")
+
+			_, err = Load(file)
+			Expect(err).To(BeAssignableToTypeOf(EnvError{}))
+			Expect(err.Error()).To(ContainSubstring("$SNIP_SEARCH"))
+		})
+	})
+
+	Context("when calling Set()", func() {
+		It("should validate and save the setting", func() {
+			c, err := Read(file)
+			Expect(err).To(BeNil())
+
+			Expect(c.Set(SHELL, "powershell")).NotTo(BeNil())
+			Expect(c.Set(SHELL, "/usr/local/bin/fish")).To(BeNil())
+			Expect(c.Set(CLIPBOARD, "file:/tmp/clip")).To(BeNil())
+			Expect(c.Set(LIBRARY, "../work")).NotTo(BeNil())
+			Expect(c.Set(SECRETS, "vault, pass")).To(BeNil())
+			Expect(c.Set(SECRETS, "vault,keychain")).NotTo(BeNil())
+			Expect(c.Set(UNLOCK_TIMEOUT, "1h")).To(BeNil())
+			Expect(c.Set(UNLOCK_TIMEOUT, "an hour")).NotTo(BeNil())
+			Expect(c.Set("pager", "less")).To(Equal(UnknownKeyError{Key: "pager"}))
+			Expect(c.Save(file)).To(BeNil())
+
+			saved, err := Read(file)
+
+			Expect(err).To(BeNil())
+			Expect(saved.Value(SHELL)).To(Equal("/usr/local/bin/fish"))
+			Expect(saved.Value(CLIPBOARD)).To(Equal("file:/tmp/clip"))
+
+			Expect(saved.Set(SHELL, "")).To(BeNil())
+			Expect(saved.IsSet(SHELL)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownKeyError error when the setting does not exist
+type UnknownKeyError struct {
+	Key string
+}
+
+func (e UnknownKeyError) Error() string {
+	return fmt.Sprintf("Unknown setting %s, use one of: %s", e.Key, strings.Join(Keys(), ", "))
+}
+
+// InvalidValueError error when the value is not accepted by the setting
+type InvalidValueError struct {
+	Key    string
+	Value  string
+	Values []string
+	Usage  string
+}
+
+func (e InvalidValueError) Error() string {
+	if len(e.Values) > 0 {
+		return fmt.Sprintf("Invalid %s '%s', use one of: %s", e.Key, e.Value, strings.Join(e.Values, ", "))
+	}
+
+	return fmt.Sprintf("Invalid %s '%s', use %s", e.Key, e.Value, e.Usage)
+}
+
+// FileError error when the config file cannot be read
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("Invalid config file %s: %s", e.File, e.Err.Error())
+}
+
+// EnvError error when an environment variable overrides a setting with an invalid value
+type EnvError struct {
+	Env string
+	Err error
+}
+
+func (e EnvError) Error() string {
+	return fmt.Sprintf("Invalid $%s: %s", e.Env, e.Err.Error())
+}
@@ -0,0 +1,179 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// MAGIC starts the encrypted data, followed by the salt, the nonce and the sealed content
+const MAGIC = "snip-crypt-1\n"
+
+const (
+	saltSize = 16
+	keySize  = 32
+)
+
+// Passphrase returns the passphrase accepted by check, e.g. by asking it until it decrypts the data.
+// check is nil when nothing is encrypted yet, a new passphrase is returned then
+type Passphrase func(check func(passphrase string) error) (string, error)
+
+// the scrypt cost parameters, recommended for interactive logins
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// keys caches the keys derived during the process, scrypt is slow on purpose and
+// each encrypted snippet has its salt
+var keys = struct {
+	sync.Mutex
+	derived map[[sha256.Size]byte][]byte
+	// salts is the salt of the content encrypted with each passphrase during the process
+	salts map[[sha256.Size]byte][]byte
+}{derived: make(map[[sha256.Size]byte][]byte), salts: make(map[[sha256.Size]byte][]byte)}
+
+// Encrypt seals the content with AES-256-GCM and a key derived from the passphrase with scrypt and a salt:
+// the salt of the content decrypted with the passphrase during the process, a random one otherwise, with a random nonce
+func Encrypt(content []byte, passphrase string) ([]byte, error) {
+	salt, err := encryptionSalt(passphrase)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	data := append([]byte(MAGIC), salt...)
+	data = append(data, nonce...)
+
+	return aead.Seal(data, nonce, content, []byte(MAGIC)), nil
+}
+
+// Decrypt opens the data sealed by Encrypt, WrongPassphraseError when the passphrase does not match
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, NotEncryptedError{}
+	}
+
+	data = data[len(MAGIC):]
+
+	if len(data) < saltSize {
+		return nil, CorruptedError{}
+	}
+
+	salt, data := data[:saltSize], data[saltSize:]
+	aead, err := newAEAD(passphrase, salt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, CorruptedError{}
+	}
+
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	content, err := aead.Open(nil, nonce, sealed, []byte(MAGIC))
+
+	if err != nil {
+		return nil, WrongPassphraseError{}
+	}
+
+	rememberSalt(passphrase, salt)
+
+	return content, nil
+}
+
+// IsEncrypted tells whether the data was sealed by Encrypt
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(MAGIC))
+}
+
+func encryptionSalt(passphrase string) ([]byte, error) {
+	keys.Lock()
+	defer keys.Unlock()
+
+	id := sha256.Sum256([]byte(passphrase))
+
+	if salt, ok := keys.salts[id]; ok {
+		return salt, nil
+	}
+
+	salt := make([]byte, saltSize)
+
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	keys.salts[id] = salt
+
+	return salt, nil
+}
+
+// rememberSalt makes the content encrypted with the passphrase during the process use the salt it decrypted,
+// whose key is derived already. The snippets encrypted with a passphrase end up sharing one salt
+func rememberSalt(passphrase string, salt []byte) {
+	keys.Lock()
+	defer keys.Unlock()
+
+	id := sha256.Sum256([]byte(passphrase))
+
+	if _, ok := keys.salts[id]; !ok {
+		keys.salts[id] = append([]byte{}, salt...)
+	}
+}
+
+// deriveKey returns the key of the passphrase and the salt, derived once per process
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	keys.Lock()
+	defer keys.Unlock()
+
+	id := sha256.Sum256(append(append([]byte(passphrase), 0), salt...))
+
+	if key, ok := keys.derived[id]; ok {
+		return key, nil
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys.derived[id] = key
+
+	return key, nil
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := deriveKey(passphrase, salt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,48 @@
+package crypt_test
+
+import (
+	. "github.com/baopham/snip/crypt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Crypt", func() {
+	It("should decrypt what it encrypted", func() {
+		data, err := Encrypt([]byte("secret content"), "passphrase")
+
+		Expect(err).To(BeNil())
+		Expect(IsEncrypted(data)).To(BeTrue())
+		Expect(string(data)).NotTo(ContainSubstring("secret content"))
+
+		content, err := Decrypt(data, "passphrase")
+
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("secret content"))
+	})
+
+	It("should use a new nonce every time and the salt of the passphrase once derived", func() {
+		first, _ := Encrypt([]byte("content"), "passphrase")
+		second, _ := Encrypt([]byte("content"), "passphrase")
+		other, _ := Encrypt([]byte("content"), "other")
+
+		Expect(first).NotTo(Equal(second))
+		Expect(first[:len(MAGIC)+16]).To(Equal(second[:len(MAGIC)+16]))
+		Expect(first[:len(MAGIC)+16]).NotTo(Equal(other[:len(MAGIC)+16]))
+	})
+
+	It("should refuse a wrong passphrase", func() {
+		data, _ := Encrypt([]byte("content"), "passphrase")
+
+		_, err := Decrypt(data, "wrong")
+
+		Expect(err).To(Equal(WrongPassphraseError{}))
+	})
+
+	It("should refuse data which is not encrypted or truncated", func() {
+		_, err := Decrypt([]byte("plain text"), "passphrase")
+		Expect(err).To(Equal(NotEncryptedError{}))
+
+		_, err = Decrypt([]byte(MAGIC+"salt"), "passphrase")
+		Expect(err).To(Equal(CorruptedError{}))
+	})
+})
@@ -0,0 +1,22 @@
+package crypt
+
+// WrongPassphraseError error when the data cannot be decrypted with the passphrase
+type WrongPassphraseError struct{}
+
+func (e WrongPassphraseError) Error() string {
+	return "Wrong passphrase"
+}
+
+// NotEncryptedError error when decrypting data which is not encrypted
+type NotEncryptedError struct{}
+
+func (e NotEncryptedError) Error() string {
+	return "The data is not encrypted"
+}
+
+// CorruptedError error when the encrypted data is truncated
+type CorruptedError struct{}
+
+func (e CorruptedError) Error() string {
+	return "The encrypted data is corrupted"
+}
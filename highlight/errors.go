@@ -0,0 +1,15 @@
+package highlight
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownThemeError error when the highlighting theme does not exist
+type UnknownThemeError struct {
+	Theme string
+}
+
+func (e UnknownThemeError) Error() string {
+	return fmt.Sprintf("Unknown theme %s, use one of: %s", e.Theme, strings.Join(Themes, ", "))
+}
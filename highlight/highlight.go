@@ -0,0 +1,428 @@
+package highlight
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+const (
+	SHELL  = "shell"
+	SQL    = "sql"
+	JSON   = "json"
+	PYTHON = "python"
+)
+
+// kind is the kind of token a rune belongs to
+type kind int
+
+const (
+	PLAIN kind = iota
+	KEYWORD
+	COMMAND
+	STRING
+	NUMBER
+	COMMENT
+	VARIABLE
+	PLACEHOLDER
+	OPERATOR
+)
+
+// language describes the tokens of a language
+type language struct {
+	comment   string
+	quotes    string
+	operators string
+	keywords  map[string]bool
+	// case insensitive keywords, e.g. SQL
+	fold bool
+	// $VAR and ${VAR} variables
+	variables bool
+	// the first word of a command is highlighted, e.g. shell
+	commands bool
+	// strings followed by a colon are keys, e.g. JSON
+	keys bool
+}
+
+var languages = map[string]*language{
+	SHELL: {
+		comment:   "#",
+		quotes:    "'\"`",
+		operators: "|&;<>()",
+		keywords: words("if then else elif fi for while until do done case esac in function " +
+			"return export local sudo time exec"),
+		variables: true,
+		commands:  true,
+	},
+	SQL: {
+		comment:   "--",
+		quotes:    "'\"`",
+		operators: "=<>*(),;",
+		keywords: words("select from where and or not in is null as join left right inner outer full on " +
+			"group by order having limit offset insert into values update set delete create alter drop " +
+			"table index view with distinct union all case when then else end asc desc like between exists " +
+			"primary key default returning explain analyze count sum avg min max"),
+		fold: true,
+	},
+	JSON: {
+		quotes:    "\"",
+		operators: "{}[]:,",
+		keywords:  words("true false null"),
+		keys:      true,
+	},
+	PYTHON: {
+		comment:   "#",
+		quotes:    "'\"",
+		operators: "=<>+-*/%()[]{}:,.",
+		keywords: words("import from as def class return if elif else for while in not and or is None True False " +
+			"with try except finally raise pass break continue lambda yield print async await global"),
+	},
+}
+
+// placeholderPattern matches the placeholders {p}, {token:secret} and the includes {@keyword}
+const placeholderPattern = `\{(@[^{}\s]+|[A-Za-z0-9_.-]+(:secret)?)\}`
+
+var placeholderRegexp = regexp.MustCompile(placeholderPattern)
+
+var placeholderPrefixRegexp = regexp.MustCompile("^" + placeholderPattern)
+
+var sqlRegexp = regexp.MustCompile(`(?i)^(select|insert|update|delete|create|alter|drop|with|explain)\b`)
+
+var pythonRegexp = regexp.MustCompile(`^(import \w|from [\w.]+ import |def \w|class \w|print\(|#!.*python)`)
+
+func words(list string) map[string]bool {
+	keywords := make(map[string]bool)
+
+	for _, word := range strings.Fields(list) {
+		keywords[word] = true
+	}
+
+	return keywords
+}
+
+// Detect guesses the language of the snippet content, shell by default
+func Detect(content string) string {
+	trimmed := strings.TrimSpace(content)
+
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		// placeholders are not valid JSON values, check the content with a value in place of them
+		valid := placeholderRegexp.ReplaceAllString(trimmed, "0")
+
+		if json.Valid([]byte(valid)) {
+			return JSON
+		}
+	}
+
+	if sqlRegexp.MatchString(trimmed) {
+		return SQL
+	}
+
+	if pythonRegexp.MatchString(trimmed) {
+		return PYTHON
+	}
+
+	return SHELL
+}
+
+// the match modes, the same as the search modes of the config
+const (
+	MATCH_FUZZY     = "fuzzy"
+	MATCH_SUBSTRING = "substring"
+	MATCH_EXACT     = "exact"
+)
+
+// Match returns the positions of the runes of the text matching the query the way the search of the mode does:
+// the runes of the query in order ignoring the case when fuzzy, the query ignoring the case with substring
+// and the whole text when exact. Nil when the text does not match
+func Match(query, text, mode string) []int {
+	query = strings.TrimSpace(query)
+
+	if query == "" {
+		return nil
+	}
+
+	switch mode {
+	case MATCH_SUBSTRING:
+		return matchSubstring([]rune(strings.ToLower(query)), []rune(text))
+	case MATCH_EXACT:
+		if strings.TrimSpace(text) != query {
+			return nil
+		}
+
+		return matchSubstring([]rune(strings.ToLower(query)), []rune(text))
+	}
+
+	var positions []int
+
+	target := []rune(strings.ToLower(query))
+	i := 0
+
+	for position, r := range []rune(text) {
+		if i < len(target) && unicode.ToLower(r) == target[i] {
+			positions = append(positions, position)
+			i++
+		}
+	}
+
+	if i < len(target) {
+		return nil
+	}
+
+	return positions
+}
+
+// matchSubstring returns the positions of the first occurrence of the lower case target in the text, ignoring its case
+func matchSubstring(target, text []rune) []int {
+	for start := 0; start+len(target) <= len(text); start++ {
+		matched := true
+
+		for i, r := range target {
+			if unicode.ToLower(text[start+i]) != r {
+				matched = false
+				break
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		positions := make([]int, len(target))
+
+		for i := range positions {
+			positions[i] = start + i
+		}
+
+		return positions
+	}
+
+	return nil
+}
+
+// Highlight colors the text as the given language, no language only highlights the matched runes.
+// The text is left as is when the colors are disabled
+func Highlight(text, lang string, matched []int) string {
+	if color.NoColor || current == nil {
+		return text
+	}
+
+	runes := []rune(text)
+	kinds := make([]kind, len(runes))
+
+	if l, ok := languages[lang]; ok {
+		kinds = tokenize(runes, l)
+	}
+
+	isMatched := make([]bool, len(runes))
+
+	for _, position := range matched {
+		if position >= 0 && position < len(runes) {
+			isMatched[position] = true
+		}
+	}
+
+	var out strings.Builder
+
+	for start := 0; start < len(runes); {
+		if runes[start] == '\n' {
+			out.WriteRune('\n')
+			start++
+			continue
+		}
+
+		end := start + 1
+
+		for end < len(runes) && runes[end] != '\n' && kinds[end] == kinds[start] && isMatched[end] == isMatched[start] {
+			end++
+		}
+
+		out.WriteString(paint(string(runes[start:end]), kinds[start], isMatched[start]))
+		start = end
+	}
+
+	return out.String()
+}
+
+func paint(text string, k kind, matched bool) string {
+	attributes := current[k]
+
+	if matched {
+		attributes = append(append([]color.Attribute{}, attributes...), matchAttributes...)
+	}
+
+	if len(attributes) == 0 {
+		return text
+	}
+
+	return color.New(attributes...).Sprint(text)
+}
+
+// tokenize returns the kind of each rune
+func tokenize(runes []rune, l *language) []kind {
+	kinds := make([]kind, len(runes))
+	command := l.commands
+
+	fill := func(from, to int, k kind) {
+		for ; from < to && from < len(runes); from++ {
+			kinds[from] = k
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		if n := placeholderAt(runes, i); n > 0 {
+			fill(i, i+n, PLACEHOLDER)
+			i += n
+			command = false
+			continue
+		}
+
+		r := runes[i]
+
+		switch {
+		case l.comment != "" && strings.HasPrefix(string(runes[i:]), l.comment) && (i == 0 || unicode.IsSpace(runes[i-1])):
+			end := i
+
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+
+			fill(i, end, COMMENT)
+			i = end
+		case strings.ContainsRune(l.quotes, r):
+			start := i
+			i = quoted(runes, kinds, i)
+
+			if l.keys && followedBy(runes, i, ':') {
+				fill(start, i, KEYWORD)
+			}
+
+			command = false
+		case l.variables && r == '$' && i+1 < len(runes):
+			end := i + 1
+
+			if runes[end] == '{' {
+				for end < len(runes) && runes[end] != '}' {
+					end++
+				}
+
+				end++
+			} else if runes[end] == '(' {
+				// command substitution, the next word is a command
+				fill(i, i+2, OPERATOR)
+				i += 2
+				command = l.commands
+				continue
+			} else {
+				for end < len(runes) && (isWord(runes[end]) || (end == i+1 && strings.ContainsRune("?!#@*$", runes[end]))) {
+					end++
+				}
+			}
+
+			fill(i, end, VARIABLE)
+			i = end
+			command = false
+		case unicode.IsDigit(r) && (i == 0 || !isWord(runes[i-1])):
+			end := i
+
+			for end < len(runes) && (isWord(runes[end]) || runes[end] == '.') {
+				end++
+			}
+
+			fill(i, end, NUMBER)
+			i = end
+			command = false
+		case isWord(r) || (command && !unicode.IsSpace(r) && !strings.ContainsRune(l.operators, r)):
+			end := i
+
+			for end < len(runes) && (isWord(runes[end]) || (command && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(l.operators, runes[end]) && placeholderAt(runes, end) == 0)) {
+				end++
+			}
+
+			word := string(runes[i:end])
+
+			if l.fold {
+				word = strings.ToLower(word)
+			}
+
+			switch {
+			case l.keywords[word]:
+				fill(i, end, KEYWORD)
+			case command && strings.Contains(word, "="):
+				// an environment variable set for the command, e.g. FOO=bar cmd
+			case command:
+				fill(i, end, COMMAND)
+				command = false
+			}
+
+			i = end
+		case strings.ContainsRune(l.operators, r):
+			fill(i, i+1, OPERATOR)
+			command = l.commands && strings.ContainsRune("|&;(", r)
+			i++
+		case r == '\n':
+			command = l.commands
+			i++
+		default:
+			i++
+		}
+	}
+
+	return kinds
+}
+
+// quoted marks the string starting at the quote, the placeholders inside stand out,
+// and returns the position after the closing quote
+func quoted(runes []rune, kinds []kind, start int) int {
+	quote := runes[start]
+	kinds[start] = STRING
+
+	for i := start + 1; i < len(runes); i++ {
+		if n := placeholderAt(runes, i); n > 0 {
+			for end := i + n; i < end; i++ {
+				kinds[i] = PLACEHOLDER
+			}
+
+			i--
+			continue
+		}
+
+		kinds[i] = STRING
+
+		if runes[i] == '\\' && quote != '\'' && i+1 < len(runes) {
+			i++
+			kinds[i] = STRING
+			continue
+		}
+
+		if runes[i] == quote {
+			return i + 1
+		}
+	}
+
+	return len(runes)
+}
+
+func placeholderAt(runes []rune, i int) int {
+	if runes[i] != '{' {
+		return 0
+	}
+
+	return len([]rune(placeholderPrefixRegexp.FindString(string(runes[i:]))))
+}
+
+func followedBy(runes []rune, i int, r rune) bool {
+	for ; i < len(runes); i++ {
+		if !unicode.IsSpace(runes[i]) {
+			return runes[i] == r
+		}
+	}
+
+	return false
+}
+
+func isWord(r rune) bool {
+	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
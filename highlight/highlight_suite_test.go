@@ -0,0 +1,13 @@
+package highlight_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Highlight Suite")
+}
@@ -0,0 +1,88 @@
+package highlight_test
+
+import (
+	. "github.com/baopham/snip/highlight"
+	"github.com/fatih/color"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Highlight", func() {
+	var noColor bool
+
+	BeforeEach(func() {
+		noColor = color.NoColor
+		color.NoColor = false
+		Expect(SetTheme(DEFAULT_THEME)).To(BeNil())
+	})
+
+	AfterEach(func() {
+		color.NoColor = noColor
+		Expect(SetTheme(DEFAULT_THEME)).To(BeNil())
+	})
+
+	Context("when calling Detect()", func() {
+		It("should detect the language of the content", func() {
+			Expect(Detect(`{"port": {p}, "tags": ["a"]}`)).To(Equal(JSON))
+			Expect(Detect("select * from users where id = {id}")).To(Equal(SQL))
+			Expect(Detect("import os\nprint(os.getcwd())")).To(Equal(PYTHON))
+			Expect(Detect("lsof -i :{p}")).To(Equal(SHELL))
+			Expect(Detect("{ echo a; echo b; } | wc -l")).To(Equal(SHELL))
+		})
+	})
+
+	Context("when calling Match()", func() {
+		It("should return the positions of the fuzzy match ignoring the case", func() {
+			Expect(Match("PRT", "lsof port", MATCH_FUZZY)).To(Equal([]int{5, 7, 8}))
+		})
+
+		It("should return the positions of the substring ignoring the case", func() {
+			Expect(Match("Po", "lsof -p port", MATCH_SUBSTRING)).To(Equal([]int{8, 9}))
+			Expect(Match("prt", "lsof port", MATCH_SUBSTRING)).To(BeNil())
+		})
+
+		It("should return the whole text when it is the query", func() {
+			Expect(Match("port", "port", MATCH_EXACT)).To(Equal([]int{0, 1, 2, 3}))
+			Expect(Match("port", "lsof port", MATCH_EXACT)).To(BeNil())
+		})
+
+		It("should return nil when the text does not match", func() {
+			Expect(Match("xyz", "lsof port", MATCH_FUZZY)).To(BeNil())
+			Expect(Match("", "lsof port", MATCH_FUZZY)).To(BeNil())
+		})
+	})
+
+	Context("when calling Highlight()", func() {
+		It("should color the tokens of the language", func() {
+			highlighted := Highlight(`lsof -i :{p} # "x"`, SHELL, nil)
+
+			Expect(highlighted).To(ContainSubstring(color.New(color.FgGreen, color.Bold).Sprint("lsof")))
+			Expect(highlighted).To(ContainSubstring(color.New(color.FgHiCyan, color.Bold).Sprint("{p}")))
+			Expect(highlighted).To(ContainSubstring(color.New(color.FgHiBlack).Sprint(`# "x"`)))
+		})
+
+		It("should make the matched runes stand out", func() {
+			highlighted := Highlight("port", "", []int{0, 1})
+
+			Expect(highlighted).To(Equal(color.New(color.Bold, color.Underline).Sprint("po") + "rt"))
+		})
+
+		It("should leave the text as is without colors", func() {
+			color.NoColor = true
+
+			Expect(Highlight("lsof -i :{p}", SHELL, []int{0})).To(Equal("lsof -i :{p}"))
+		})
+
+		It("should leave the text as is with the none theme", func() {
+			Expect(SetTheme(NO_THEME)).To(BeNil())
+
+			Expect(Highlight("lsof -i :{p}", SHELL, []int{0})).To(Equal("lsof -i :{p}"))
+		})
+	})
+
+	Context("when calling SetTheme() with an unknown theme", func() {
+		It("should return UnknownThemeError", func() {
+			Expect(SetTheme("neon")).To(Equal(UnknownThemeError{Theme: "neon"}))
+		})
+	})
+})
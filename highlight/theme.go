@@ -0,0 +1,66 @@
+package highlight
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// theme gives the colors of each kind of token
+type theme map[kind][]color.Attribute
+
+// matchAttributes are added to the runes matching the search
+var matchAttributes = []color.Attribute{color.Bold, color.Underline}
+
+const (
+	DEFAULT_THEME = "default"
+	LIGHT_THEME   = "light"
+	NO_THEME      = "none"
+)
+
+var Themes = []string{DEFAULT_THEME, LIGHT_THEME, NO_THEME}
+
+var themes = map[string]theme{
+	DEFAULT_THEME: {
+		KEYWORD:     {color.FgMagenta},
+		COMMAND:     {color.FgGreen, color.Bold},
+		STRING:      {color.FgYellow},
+		NUMBER:      {color.FgCyan},
+		COMMENT:     {color.FgHiBlack},
+		VARIABLE:    {color.FgHiBlue},
+		PLACEHOLDER: {color.FgHiCyan, color.Bold},
+		OPERATOR:    {color.FgRed},
+	},
+	LIGHT_THEME: {
+		KEYWORD:     {color.FgBlue},
+		COMMAND:     {color.FgGreen, color.Bold},
+		STRING:      {color.FgRed},
+		NUMBER:      {color.FgMagenta},
+		COMMENT:     {color.FgHiBlack},
+		VARIABLE:    {color.FgCyan},
+		PLACEHOLDER: {color.FgBlue, color.Bold},
+		OPERATOR:    {color.FgHiBlack},
+	},
+	NO_THEME: nil,
+}
+
+var current = themes[DEFAULT_THEME]
+
+// SetTheme chooses the colors of the highlighting, none disables it
+func SetTheme(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if name == "" {
+		name = DEFAULT_THEME
+	}
+
+	t, ok := themes[name]
+
+	if !ok {
+		return UnknownThemeError{Theme: name}
+	}
+
+	current = t
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+
+	"github.com/baopham/snip/snippet"
+	"github.com/baopham/snip/util"
+)
+
+// Redacted replaces the values of secret placeholders in the history log
+const Redacted = "<redacted>"
+
+// Entry represents one execution of a snippet
+type Entry struct {
+	Time    time.Time `json:"time" yaml:"time"`
+	Keyword string    `json:"keyword" yaml:"keyword"`
+	// Source is the snippets file of the snippet, empty for the executions saved before it was recorded
+	Source       string            `json:"source,omitempty" yaml:"source,omitempty"`
+	Placeholders map[string]string `json:"placeholders" yaml:"placeholders"`
+	Command      string            `json:"command" yaml:"command"`
+	Dir          string            `json:"dir" yaml:"dir"`
+	ExitCode     int               `json:"exit_code" yaml:"exit_code"`
+	Duration     time.Duration     `json:"duration" yaml:"duration"`
+}
+
+// IsRedacted tells if the value of the given placeholder was redacted
+func (e *Entry) IsRedacted(name string) bool {
+	return e.Placeholders[name] == Redacted
+}
+
+// Append the entry to the history log
+func Append(entry Entry, filePath string) error {
+	if err := os.MkdirAll(path.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, snippet.FileMode)
+
+	if err != nil {
+		return err
+	}
+
+	defer util.Check(file.Close)
+
+	return json.NewEncoder(file).Encode(entry)
+}
+
+// Load all the entries of the history log, oldest first
+func Load(filePath string) ([]*Entry, error) {
+	var entries []*Entry
+
+	file, err := os.Open(filePath)
+
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+
+	if err != nil {
+		return entries, err
+	}
+
+	defer util.Check(file.Close)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := &Entry{}
+
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return entries, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// HistoryFile returns the default file path to the history log
+func HistoryFile() (string, error) {
+	dir, err := snippet.SnippetDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(dir, "history.jsonl"), nil
+}
@@ -0,0 +1,75 @@
+package history_test
+
+import (
+	. "github.com/baopham/snip/history"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"os"
+	"path"
+	"time"
+)
+
+var _ = Describe("History", func() {
+	var fakeFilePath string
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+
+		if err != nil {
+			panic(err)
+		}
+
+		fakeFilePath = path.Join(dir, ".history")
+
+		os.Remove(fakeFilePath)
+	})
+
+	AfterEach(func() {
+		os.Remove(fakeFilePath)
+	})
+
+	Context("when calling Load() without any history log", func() {
+		It("should return no entries", func() {
+			entries, err := Load(fakeFilePath)
+
+			Expect(err).To(BeNil())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+
+	Context("when calling Append()", func() {
+		It("should append the entries to the history log", func() {
+			first := Entry{
+				Time:         time.Date(2017, 1, 1, 10, 0, 0, 0, time.UTC),
+				Keyword:      "port",
+				Placeholders: map[string]string{"p": "9000"},
+				Command:      "lsof -i :9000",
+				Dir:          "/tmp",
+				ExitCode:     1,
+				Duration:     time.Second,
+			}
+
+			second := Entry{
+				Time:         time.Date(2017, 1, 1, 11, 0, 0, 0, time.UTC),
+				Keyword:      "login",
+				Placeholders: map[string]string{"token": Redacted},
+				Command:      "login " + Redacted,
+			}
+
+			Expect(Append(first, fakeFilePath)).To(Succeed())
+			Expect(Append(second, fakeFilePath)).To(Succeed())
+
+			entries, err := Load(fakeFilePath)
+
+			Expect(err).To(BeNil())
+			Expect(entries).To(HaveLen(2))
+			Expect(*entries[0]).To(Equal(first))
+			Expect(*entries[1]).To(Equal(second))
+
+			By("telling which values were redacted")
+
+			Expect(entries[1].IsRedacted("token")).To(BeTrue())
+			Expect(entries[0].IsRedacted("p")).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,15 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownSourceError error when snip cannot import from the given snippet manager
+type UnknownSourceError struct {
+	Source string
+}
+
+func (e UnknownSourceError) Error() string {
+	return fmt.Sprintf("Cannot import from %s, use one of: %s", e.Source, strings.Join(Sources, ", "))
+}
@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/baopham/snip/snippet"
+	"gopkg.in/yaml.v2"
+)
+
+type espansoFile struct {
+	Matches []espansoMatch `yaml:"matches"`
+}
+
+type espansoMatch struct {
+	Trigger  string   `yaml:"trigger"`
+	Triggers []string `yaml:"triggers"`
+	Regex    string   `yaml:"regex"`
+	Replace  string   `yaml:"replace"`
+	Markdown string   `yaml:"markdown"`
+	HTML     string   `yaml:"html"`
+	Form     string   `yaml:"form"`
+	Label    string   `yaml:"label"`
+}
+
+var (
+	// {{name}} in the replacements
+	espansoVariableRegexp = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+	// [[name]] in the forms
+	espansoFieldRegexp = regexp.MustCompile(`\[\[\s*([^\[\]]+?)\s*\]\]`)
+)
+
+// readEspanso reads an Espanso match file, the variables and form fields become placeholders
+func readEspanso(b []byte) (*Result, error) {
+	file := espansoFile{}
+
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+
+	for _, match := range file.Matches {
+		trigger := match.Trigger
+
+		if trigger == "" && len(match.Triggers) > 0 {
+			trigger = match.Triggers[0]
+		}
+
+		if match.Regex != "" {
+			result.skip(match.Regex, "regex triggers are not supported")
+			continue
+		}
+
+		content := match.Replace
+
+		for _, alternative := range []string{match.Markdown, match.HTML, match.Form} {
+			if content == "" {
+				content = alternative
+			}
+		}
+
+		if content == "" {
+			result.skip(trigger, "only text replacements are supported")
+			continue
+		}
+
+		k := keyword(strings.TrimLeft(trigger, ":;/"))
+
+		if k == "" {
+			result.skip(trigger, "no keyword can be made out of the trigger")
+			continue
+		}
+
+		for _, r := range []*regexp.Regexp{espansoVariableRegexp, espansoFieldRegexp} {
+			re := r
+			content = re.ReplaceAllStringFunc(content, func(variable string) string {
+				return placeholder(re.FindStringSubmatch(variable)[1])
+			})
+		}
+
+		result.add(&snippet.Snippet{
+			Keyword:     k,
+			Content:     strings.TrimRight(content, "\n"),
+			Description: match.Label,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/baopham/snip/snippet"
+)
+
+const (
+	SNIP    = "snip"
+	PET     = "pet"
+	NAVI    = "navi"
+	TLDR    = "tldr"
+	VSCODE  = "vscode"
+	ESPANSO = "espanso"
+)
+
+var Sources = []string{SNIP, PET, NAVI, TLDR, VSCODE, ESPANSO}
+
+// Skipped is an entry of the imported file that could not be converted
+type Skipped struct {
+	Name   string
+	Reason string
+}
+
+// Result of the conversion of a file of another snippet manager
+type Result struct {
+	Snippets []*snippet.Snippet
+	Skipped  []Skipped
+	keywords map[string]bool
+}
+
+func (r *Result) add(s *snippet.Snippet) {
+	if r.keywords == nil {
+		r.keywords = make(map[string]bool)
+	}
+
+	keyword := s.Keyword
+
+	// the same keyword can come up twice, e.g. two tldr examples with the same description
+	for i := 2; r.keywords[keyword]; i++ {
+		keyword = s.Keyword + "-" + strconv.Itoa(i)
+	}
+
+	s.Keyword = keyword
+	r.keywords[keyword] = true
+	r.Snippets = append(r.Snippets, s)
+}
+
+func (r *Result) skip(name, reason string) {
+	r.Skipped = append(r.Skipped, Skipped{Name: name, Reason: reason})
+}
+
+// Read converts the snippets of the given source, e.g. pet, into snip snippets
+func Read(source string, r io.Reader) (*Result, error) {
+	b, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case SNIP:
+		return readSnip(b)
+	case PET:
+		return readPet(b)
+	case NAVI:
+		return readNavi(b)
+	case TLDR:
+		return readTldr(b)
+	case VSCODE:
+		return readVSCode(b)
+	case ESPANSO:
+		return readEspanso(b)
+	}
+
+	return nil, UnknownSourceError{Source: source}
+}
+
+// readSnip reads the snippets file of another snip library
+func readSnip(b []byte) (*Result, error) {
+	snippets, err := snippet.Parse(bytes.NewReader(b))
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+
+	for _, s := range snippets {
+		result.add(s)
+	}
+
+	return result, nil
+}
+
+var invalidNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+var invalidKeywordRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// placeholder converts a placeholder of another snippet manager into {name}
+func placeholder(name string) string {
+	name = strings.Trim(invalidNameRegexp.ReplaceAllString(strings.TrimSpace(name), "_"), "_")
+
+	if name == "" {
+		name = "p"
+	}
+
+	return "{" + name + "}"
+}
+
+// keyword makes a keyword out of a text, e.g. "List the files" becomes list-the-files
+func keyword(text string) string {
+	k := strings.Trim(invalidKeywordRegexp.ReplaceAllString(strings.ToLower(text), "-"), "-")
+
+	if len(k) > 40 {
+		k = strings.TrimRight(k[:40], "-")
+	}
+
+	return k
+}
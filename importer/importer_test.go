@@ -0,0 +1,119 @@
+package importer_test
+
+import (
+	"strings"
+
+	. "github.com/baopham/snip/importer"
+	"github.com/baopham/snip/snippet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Importer", func() {
+	read := func(source, content string) *Result {
+		result, err := Read(source, strings.NewReader(content))
+
+		Expect(err).To(BeNil())
+
+		return result
+	}
+
+	Context("when reading pet snippets", func() {
+		It("should convert the parameters", func() {
+			result := read(PET, `
+[[snippets]]
+  description = "Find processes on a port"
+  command = "lsof -i :<port=8080> -u <user>"
+  tag = ["net"]
+
+[[snippets]]
+  description = "Nothing"
+  command = ""
+`)
+
+			Expect(result.Snippets).To(Equal([]*snippet.Snippet{
+				{Keyword: "find-processes-on-a-port", Content: "lsof -i :{port} -u {user}", Description: "Find processes on a port", Tags: []string{"net"}},
+			}))
+			Expect(result.Skipped).To(Equal([]Skipped{{Name: "Nothing", Reason: "no command"}}))
+		})
+	})
+
+	Context("when reading a navi cheat", func() {
+		It("should convert the variables and skip the suggestions", func() {
+			result := read(NAVI, `% git, code
+
+# Change branch
+git checkout <branch>
+
+$ branch: git branch | awk '{print $NF}'
+`)
+
+			Expect(result.Snippets).To(Equal([]*snippet.Snippet{
+				{Keyword: "change-branch", Content: "git checkout {branch}", Description: "Change branch", Tags: []string{"git", "code"}},
+			}))
+			Expect(result.Skipped).To(HaveLen(1))
+			Expect(result.Skipped[0].Name).To(Equal("$ branch"))
+		})
+	})
+
+	Context("when reading a tldr page", func() {
+		It("should convert the placeholders and the options", func() {
+			result := read(TLDR, "# tar\n\n> Archiving utility.\n\n- Create an archive from files:\n\n`tar {{[-c|--create]}} -f {{target.tar}} {{path/to/file}}`\n")
+
+			Expect(result.Snippets).To(Equal([]*snippet.Snippet{
+				{Keyword: "tar-create-an-archive-from-files", Content: "tar -c -f {target.tar} {path_to_file}", Description: "Create an archive from files", Tags: []string{"tar"}},
+			}))
+		})
+	})
+
+	Context("when reading VS Code snippets", func() {
+		It("should convert the tabstops and variables", func() {
+			result := read(VSCODE, `{
+	// a comment
+	"Print": {
+		"prefix": ["log", "print"],
+		"body": ["console.log('${1:message}', $1, \\$2, ${2|a,b|});", "$0"]
+	},
+	"Empty": {"prefix": "empty", "body": []}
+}`)
+
+			Expect(result.Snippets).To(Equal([]*snippet.Snippet{
+				{Keyword: "log", Content: "console.log('{message}', {message}, $2, {p2});", Description: "Print"},
+			}))
+			Expect(result.Skipped).To(Equal([]Skipped{{Name: "Empty", Reason: "no body"}}))
+		})
+	})
+
+	Context("when reading Espanso matches", func() {
+		It("should convert the variables and skip the regex triggers", func() {
+			result := read(ESPANSO, `matches:
+  - trigger: ":greet"
+    replace: "Hello {{name}}, see you {{ form1.day }}"
+  - regex: ":(?P<x>.*)"
+    replace: "{{x}}"
+`)
+
+			Expect(result.Snippets).To(Equal([]*snippet.Snippet{
+				{Keyword: "greet", Content: "Hello {name}, see you {form1.day}"},
+			}))
+			Expect(result.Skipped).To(HaveLen(1))
+		})
+	})
+
+	Context("when reading duplicate keywords", func() {
+		It("should number the next ones", func() {
+			result := read(NAVI, "# List\nls\n\n# List\nls -la\n")
+
+			Expect(result.Snippets[0].Keyword).To(Equal("list"))
+			Expect(result.Snippets[1].Keyword).To(Equal("list-2"))
+		})
+	})
+
+	Context("when reading an unknown source", func() {
+		It("should return UnknownSourceError", func() {
+			_, err := Read("boom", strings.NewReader(""))
+
+			Expect(err).To(Equal(UnknownSourceError{Source: "boom"}))
+		})
+	})
+})
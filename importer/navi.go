@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/baopham/snip/snippet"
+)
+
+// naviVariableRegexp matches the navi variables <name>
+var naviVariableRegexp = regexp.MustCompile(`<([A-Za-z0-9_-]+)>`)
+
+// readNavi reads a .cheat file of navi: % tags, # description, the command lines and $ variable suggestions
+func readNavi(b []byte) (*Result, error) {
+	result := &Result{}
+
+	var (
+		tags        []string
+		description string
+		command     []string
+	)
+
+	flush := func() {
+		if len(command) == 0 {
+			return
+		}
+
+		content := naviVariableRegexp.ReplaceAllStringFunc(strings.Join(command, "\n"), func(variable string) string {
+			return placeholder(naviVariableRegexp.FindStringSubmatch(variable)[1])
+		})
+
+		k := keyword(description)
+
+		if k == "" {
+			k = keyword(strings.Fields(content)[0])
+		}
+
+		result.add(&snippet.Snippet{
+			Keyword:     k,
+			Content:     content,
+			Description: description,
+			Tags:        tags,
+		})
+
+		command, description = nil, ""
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, ";"):
+			// a comment
+		case strings.HasPrefix(trimmed, "%"):
+			flush()
+			tags = snippet.ParseTags(trimmed[1:])
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			description = strings.TrimSpace(trimmed[1:])
+		case strings.HasPrefix(trimmed, "$"):
+			flush()
+			name := strings.TrimSpace(strings.SplitN(trimmed[1:], ":", 2)[0])
+			result.skip("$ "+name, "the suggestions of a variable are not supported, it is prompted as a placeholder")
+		case strings.HasPrefix(trimmed, "@"):
+			flush()
+			result.skip(trimmed, "extending another cheat is not supported")
+		default:
+			command = append(command, strings.TrimRight(line, " \t\r"))
+		}
+	}
+
+	flush()
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/baopham/snip/snippet"
+)
+
+type petFile struct {
+	Snippets []petSnippet `toml:"snippets"`
+}
+
+type petSnippet struct {
+	Description string   `toml:"description"`
+	Command     string   `toml:"command"`
+	Tag         []string `toml:"tag"`
+}
+
+// petParamRegexp matches the pet parameters <name> and <name=default>
+var petParamRegexp = regexp.MustCompile(`<([^<>=\s][^<>=]*?)(=[^<>]*)?>`)
+
+// readPet reads the snippet.toml of pet
+func readPet(b []byte) (*Result, error) {
+	file := petFile{}
+
+	if err := toml.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+
+	for _, pet := range file.Snippets {
+		command := strings.TrimSpace(pet.Command)
+
+		if command == "" {
+			result.skip(pet.Description, "no command")
+			continue
+		}
+
+		k := keyword(pet.Description)
+
+		if k == "" {
+			k = keyword(strings.Fields(command)[0])
+		}
+
+		content := petParamRegexp.ReplaceAllStringFunc(command, func(param string) string {
+			return placeholder(petParamRegexp.FindStringSubmatch(param)[1])
+		})
+
+		result.add(&snippet.Snippet{
+			Keyword:     k,
+			Content:     content,
+			Description: strings.TrimSpace(pet.Description),
+			Tags:        pet.Tag,
+		})
+	}
+
+	return result, nil
+}
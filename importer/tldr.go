@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/baopham/snip/snippet"
+)
+
+// tldrPlaceholderRegexp matches the tldr placeholders {{path/to/file}}
+var tldrPlaceholderRegexp = regexp.MustCompile(`\{\{(.*?)\}\}`)
+
+// readTldr reads a tldr page: # command, > summary, - description of the example and `example`
+func readTldr(b []byte) (*Result, error) {
+	result := &Result{}
+
+	var page, description string
+
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "# "):
+			page = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, "- "):
+			description = strings.TrimSuffix(strings.TrimSpace(line[2:]), ":")
+		case len(line) > 1 && strings.HasPrefix(line, "`") && strings.HasSuffix(line, "`"):
+			if page == "" {
+				result.skip(line, "the example is not in a tldr page")
+				continue
+			}
+
+			content := tldrPlaceholderRegexp.ReplaceAllStringFunc(strings.Trim(line, "`"), tldrPlaceholder)
+
+			result.add(&snippet.Snippet{
+				Keyword:     keyword(page + " " + description),
+				Content:     content,
+				Description: description,
+				Tags:        []string{page},
+			})
+
+			description = ""
+		}
+	}
+
+	return result, nil
+}
+
+// tldrPlaceholder converts {{path/to/file}} into {path_to_file}, an option
+// written both ways, e.g. {{[-v|--verbose]}}, becomes its short form
+func tldrPlaceholder(text string) string {
+	inner := tldrPlaceholderRegexp.FindStringSubmatch(text)[1]
+
+	if strings.HasPrefix(inner, "[") && strings.HasSuffix(inner, "]") && strings.Contains(inner, "|") {
+		return strings.SplitN(strings.Trim(inner, "[]"), "|", 2)[0]
+	}
+
+	return placeholder(inner)
+}
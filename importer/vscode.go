@@ -0,0 +1,186 @@
+package importer
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/baopham/snip/snippet"
+)
+
+type vscodeSnippet struct {
+	Prefix      json.RawMessage `json:"prefix"`
+	Body        json.RawMessage `json:"body"`
+	Description string          `json:"description"`
+}
+
+var (
+	// ${1:name}
+	vscodeDefaultRegexp = regexp.MustCompile(`\$\{(\d+):([^{}]*)\}`)
+	// ${1|a,b|}
+	vscodeChoiceRegexp = regexp.MustCompile(`\$\{(\d+)\|[^{}]*\|\}`)
+	// $1 and ${1}
+	vscodeTabstopRegexp = regexp.MustCompile(`\$(\d+)|\$\{(\d+)\}`)
+	// $TM_FILENAME, ${TM_FILENAME} and ${TM_FILENAME:default}
+	vscodeVariableRegexp = regexp.MustCompile(`\$([A-Z_][A-Z0-9_]*)|\$\{([A-Z_][A-Z0-9_]*)(:[^{}]*)?\}`)
+)
+
+// escapedDollar stands for \$ while converting the placeholders
+const escapedDollar = "\x00"
+
+// readVSCode reads a VS Code snippets file: {"name": {"prefix": "for", "body": ["..."], "description": "..."}}
+func readVSCode(b []byte) (*Result, error) {
+	snippets := make(map[string]vscodeSnippet)
+
+	if err := json.Unmarshal(stripComments(b), &snippets); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(snippets))
+
+	for name := range snippets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	result := &Result{}
+
+	for _, name := range names {
+		vscode := snippets[name]
+		prefixes, body := stringOrList(vscode.Prefix), stringOrList(vscode.Body)
+
+		if len(body) == 0 {
+			result.skip(name, "no body")
+			continue
+		}
+
+		k := name
+
+		if len(prefixes) > 0 {
+			k = prefixes[0]
+		}
+
+		description := vscode.Description
+
+		if description == "" {
+			description = name
+		}
+
+		result.add(&snippet.Snippet{
+			Keyword:     strings.Join(strings.Fields(k), "-"),
+			Content:     strings.TrimRight(vscodePlaceholders(strings.Join(body, "\n")), " \t\n"),
+			Description: description,
+		})
+	}
+
+	return result, nil
+}
+
+// vscodePlaceholders converts the tabstops and variables into placeholders,
+// a tabstop is named after its default value, e.g. ${1:port} becomes {port}, otherwise {p1}
+func vscodePlaceholders(body string) string {
+	body = strings.Replace(body, `\$`, escapedDollar, -1)
+	names := make(map[string]string)
+
+	for _, match := range vscodeDefaultRegexp.FindAllStringSubmatch(body, -1) {
+		if _, ok := names[match[1]]; !ok && strings.TrimSpace(match[2]) != "" {
+			names[match[1]] = placeholder(match[2])
+		}
+	}
+
+	tabstop := func(number string) string {
+		if number == "0" {
+			return ""
+		}
+
+		if name, ok := names[number]; ok {
+			return name
+		}
+
+		return placeholder("p" + number)
+	}
+
+	body = vscodeDefaultRegexp.ReplaceAllStringFunc(body, func(text string) string {
+		return tabstop(vscodeDefaultRegexp.FindStringSubmatch(text)[1])
+	})
+
+	body = vscodeChoiceRegexp.ReplaceAllStringFunc(body, func(text string) string {
+		return tabstop(vscodeChoiceRegexp.FindStringSubmatch(text)[1])
+	})
+
+	body = vscodeTabstopRegexp.ReplaceAllStringFunc(body, func(text string) string {
+		match := vscodeTabstopRegexp.FindStringSubmatch(text)
+		return tabstop(match[1] + match[2])
+	})
+
+	body = vscodeVariableRegexp.ReplaceAllStringFunc(body, func(text string) string {
+		match := vscodeVariableRegexp.FindStringSubmatch(text)
+		return placeholder(strings.ToLower(match[1] + match[2]))
+	})
+
+	return strings.Replace(body, escapedDollar, "$", -1)
+}
+
+// stringOrList decodes a JSON string or list of strings
+func stringOrList(raw json.RawMessage) []string {
+	var list []string
+
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+
+	var single string
+
+	if err := json.Unmarshal(raw, &single); err == nil && single != "" {
+		return []string{single}
+	}
+
+	return nil
+}
+
+// stripComments removes the // and /* */ comments VS Code allows in its JSON files
+func stripComments(b []byte) []byte {
+	var (
+		out      []byte
+		inString bool
+	)
+
+	for i := 0; i < len(b); i++ {
+		switch {
+		case inString:
+			out = append(out, b[i])
+
+			if b[i] == '\\' && i+1 < len(b) {
+				i++
+				out = append(out, b[i])
+			} else if b[i] == '"' {
+				inString = false
+			}
+		case b[i] == '"':
+			inString = true
+			out = append(out, b[i])
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			for i < len(b) && b[i] != '\n' {
+				i++
+			}
+
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			i += 2
+
+			for i+1 < len(b) && !(b[i] == '*' && b[i+1] == '/') {
+				i++
+			}
+
+			i++
+		default:
+			out = append(out, b[i])
+		}
+	}
+
+	return out
+}
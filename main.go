@@ -2,6 +2,10 @@ package main
 
 import (
 	snippetCli "github.com/baopham/snip/cli"
+	"github.com/baopham/snip/config"
+	"github.com/baopham/snip/highlight"
+	"github.com/baopham/snip/snippet"
+	"github.com/baopham/snip/util"
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
 	"os"
@@ -21,11 +25,156 @@ func Action(fn func(c *cli.Context) error) func(c *cli.Context) error {
 	return actor
 }
 
+var outputFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "print, p",
+		Usage: "print the snippet to stdout instead of the clipboard, default when stdout is not a terminal",
+	},
+	cli.BoolFlag{
+		Name:  "null, 0",
+		Usage: "print the snippet terminated by a NUL character",
+	},
+	cli.DurationFlag{
+		Name:  "clear-after",
+		Usage: "restore the previous clipboard content after this time, e.g. 30s, default for sensitive snippets",
+	},
+}
+
+var formatFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format",
+		Value: "table",
+		Usage: "output format: table, json, yaml, csv or tsv",
+	},
+	cli.StringFlag{
+		Name:  "template, t",
+		Usage: "Go template executed for each item with the fields of the JSON format, e.g. '{{.keyword}}'",
+	},
+	cli.BoolFlag{
+		Name:  "wide, w",
+		Usage: "do not truncate or wrap the table to the width of the terminal",
+	},
+}
+
+var libFlag = cli.StringFlag{
+	Name:  "lib",
+	Usage: "named library used instead of the default library, see snip lib list",
+}
+
+var fileFlag = cli.StringFlag{
+	Name:   "file",
+	Usage:  "snippet file used instead of the library, e.g. a file shared by the team",
+	EnvVar: "SNIP_FILE",
+}
+
+var profileFlag = cli.StringFlag{
+	Name:  "profile",
+	Usage: "profile filling the placeholders, instead of the active profile, see snip profile list",
+}
+
+var columnsFlag = cli.StringFlag{
+	Name:  "columns",
+	Usage: "comma separated columns of the table: keyword, content, desc, tags, type, source",
+}
+
+// loadConfig returns the config file overridden by the environment, the defaults when it is invalid
+// initLibrary migrates ~/.snip before a command runs,
+// the library is not used with --file or $SNIP_FILE, given before or after the command
+func initLibrary(c *cli.Context) error {
+	if c.String("file") != "" || c.GlobalString("file") != "" {
+		return nil
+	}
+
+	if err := snippet.Init(); err != nil {
+		if _, ok := err.(snippet.MigrationError); !ok {
+			color.Red(err.Error())
+			return err
+		}
+
+		color.Yellow(err.Error())
+	}
+
+	return nil
+}
+
+func loadConfig() (*config.Config, error) {
+	file, err := config.File()
+
+	if err != nil {
+		return &config.Config{}, err
+	}
+
+	conf, err := config.Load(file)
+
+	if err != nil {
+		return &config.Config{}, err
+	}
+
+	return conf, nil
+}
+
 func main() {
+	if os.Getenv("NO_COLOR") != "" || !util.IsTerminal(os.Stdout) {
+		color.NoColor = true
+	}
+
 	app := cli.NewApp()
 	app.Version = "3.0.0"
 	app.Usage = "Save snippets: commands, texts, emoji, etc."
 	app.EnableBashCompletion = true
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:   "clipboard",
+			Usage:  "clipboard provider: system, osc52, tmux, stdout, file or file:/some/path, detected by default",
+			EnvVar: "SNIP_CLIPBOARD",
+		},
+		cli.StringFlag{
+			Name:   "theme",
+			Value:  highlight.DEFAULT_THEME,
+			Usage:  "colors of the highlighted snippets: default, light or none",
+			EnvVar: "SNIP_THEME",
+		},
+		libFlag,
+		fileFlag,
+		profileFlag,
+	}
+	app.Before = func(c *cli.Context) error {
+		snippet.Passphrase = snippetCli.Passphrase
+
+		conf, err := loadConfig()
+
+		if err != nil {
+			color.Red(err.Error())
+
+			// the config commands fix the config file
+			if c.Args().First() != "config" {
+				return err
+			}
+		}
+
+		snippetCli.Configure(conf)
+
+		switch conf.Value(config.COLOR) {
+		case config.COLOR_ALWAYS:
+			color.NoColor = false
+		case config.COLOR_NEVER:
+			color.NoColor = true
+		}
+
+		theme := c.GlobalString("theme")
+
+		if !c.GlobalIsSet("theme") {
+			theme = conf.Value(config.THEME)
+		}
+
+		err = highlight.SetTheme(theme)
+
+		if err != nil {
+			color.Red(err.Error())
+		}
+
+		return err
+	}
 	app.Commands = []cli.Command{
 		{
 			Name:    "add",
@@ -44,14 +193,69 @@ func main() {
 					Name:  "description, desc",
 					Usage: "the snippet description",
 				},
+				cli.StringFlag{
+					Name:  "tags",
+					Usage: "comma separated tags, e.g. k8s,db",
+				},
+				cli.BoolFlag{
+					Name:  "runbook",
+					Usage: "save a runbook: one step per line, lines starting with # are notes",
+				},
+				cli.BoolFlag{
+					Name:  "sensitive",
+					Usage: "clear the snippet from the clipboard after 30s and hide it from the listings",
+				},
+				cli.BoolFlag{
+					Name:  "encrypt",
+					Usage: "encrypt the content of the snippet in the snippets file with a passphrase",
+				},
+				cli.BoolFlag{
+					Name:  "local",
+					Usage: "save the snippet in the project library, .snip/snippets.csv at the root of the repository by default",
+				},
+				cli.BoolFlag{
+					Name:  "global",
+					Usage: "save the snippet in the global library, the default",
+				},
+				cli.BoolFlag{
+					Name:  "parameterize",
+					Usage: "propose to turn the ports, IPs, hosts, paths, UUIDs, numbers and quoted strings into placeholders",
+				},
 			},
 			Action: Action(snippetCli.Add),
 		},
 		{
-			Name:         "search",
-			Aliases:      []string{"s"},
-			Usage:        "search for snippets: snip search port",
-			Action:       Action(snippetCli.Search),
+			Name:    "search",
+			Aliases: []string{"s"},
+			Usage:   "search for snippets: snip search port",
+			Action:  Action(snippetCli.Search),
+			Flags: append([]cli.Flag{
+				cli.BoolFlag{
+					Name:  "reveal",
+					Usage: "include the sensitive snippets",
+				},
+				cli.BoolFlag{
+					Name:  "all-libs",
+					Usage: "search every named library instead of the current one",
+				},
+				columnsFlag,
+			}, formatFlags...),
+			BashComplete: snippetCli.Autocomplete,
+		},
+		{
+			Name:   "show",
+			Usage:  "show a snippet with its includes expanded: snip show port",
+			Action: Action(snippetCli.Show),
+			Flags: append([]cli.Flag{
+				cli.BoolFlag{
+					Name:  "raw",
+					Usage: "show the content as saved, without expanding the includes",
+				},
+				cli.BoolFlag{
+					Name:  "reveal",
+					Usage: "show the content of a sensitive snippet",
+				},
+			}, formatFlags...),
 			BashComplete: snippetCli.Autocomplete,
 		},
 		{
@@ -60,6 +264,24 @@ func main() {
 			Usage:        "generate the snippet by keyword: snip g port p={9000}",
 			Action:       Action(snippetCli.Generate),
 			BashComplete: snippetCli.Autocomplete,
+			Flags:        outputFlags,
+		},
+		{
+			Name:         "pick",
+			Usage:        "choose a snippet, fill its placeholders and generate it: snip pick",
+			Action:       Action(snippetCli.Pick),
+			BashComplete: snippetCli.Autocomplete,
+			Flags: append([]cli.Flag{
+				cli.BoolFlag{
+					Name:  "reveal",
+					Usage: "show the content of the sensitive snippets to pick them",
+				},
+			}, outputFlags...),
+		},
+		{
+			Name:   "shell-init",
+			Usage:  "print the shell widget inserting a snippet in the command line with Ctrl-G: eval \"$(snip shell-init bash)\"",
+			Action: Action(snippetCli.ShellInit),
 		},
 		{
 			Name:    "execute",
@@ -75,16 +297,326 @@ func main() {
 					Name:  "force, f",
 					Usage: "skip the prompt and force to execute",
 				},
+				cli.BoolFlag{
+					Name:  "matrix, m",
+					Usage: "run the snippet for every combination of the comma separated placeholder values, e.g. p=8000,8001",
+				},
+				cli.IntFlag{
+					Name:  "parallel",
+					Value: 1,
+					Usage: "number of runs of the --matrix executed at the same time",
+				},
+				cli.BoolFlag{
+					Name:  "fail-fast",
+					Usage: "stop the remaining runs as soon as one fails",
+				},
 			},
 			BashComplete: snippetCli.Autocomplete,
 		},
 		{
-			Name:         "list",
-			Aliases:      []string{"l"},
-			Usage:        "list all saved snippets: snip list",
-			Action:       Action(snippetCli.List),
+			Name:   "history",
+			Usage:  "show the executed snippets: snip history -k port",
+			Action: Action(snippetCli.History),
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "keyword, k",
+					Usage: "only show the executions of this snippet",
+				},
+				cli.IntFlag{
+					Name:  "limit, n",
+					Value: 20,
+					Usage: "show the last n executions, 0 to show all",
+				},
+				cli.BoolFlag{
+					Name:  "failed",
+					Usage: "only show the executions that failed",
+				},
+			}, formatFlags...),
+		},
+		{
+			Name:   "again",
+			Usage:  "re-run a previous execution with the same values: snip again 12",
+			Action: Action(snippetCli.Again),
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "output, o",
+					Usage: "execute the snippet and save the output to clipboard",
+				},
+				cli.BoolFlag{
+					Name:  "force, f",
+					Usage: "skip the prompt and force to execute",
+				},
+			},
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Usage:   "list all saved snippets: snip list",
+			Action:  Action(snippetCli.List),
+			Flags: append([]cli.Flag{
+				cli.BoolFlag{
+					Name:  "reveal",
+					Usage: "include the sensitive snippets",
+				},
+				columnsFlag,
+			}, formatFlags...),
 			BashComplete: snippetCli.Autocomplete,
 		},
+		{
+			Name:   "export",
+			Usage:  "export the snippets as JSON, YAML or a Markdown or HTML cheat sheet: snip export --format markdown -o snippets.md",
+			Action: Action(snippetCli.Export),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "format",
+					Value: "json",
+					Usage: "json, yaml, csv, tsv, markdown or html",
+				},
+				cli.StringFlag{
+					Name:  "tag",
+					Usage: "only export the snippets with this tag",
+				},
+				cli.StringFlag{
+					Name:  "output, o",
+					Usage: "write to this file instead of stdout",
+				},
+				cli.BoolFlag{
+					Name:  "reveal",
+					Usage: "include the sensitive snippets",
+				},
+				cli.BoolFlag{
+					Name:  "decrypt",
+					Usage: "include the encrypted snippets, in plaintext",
+				},
+			},
+		},
+		{
+			Name:    "import",
+			Aliases: []string{"merge"},
+			Usage:   "merge another snip library or import another snippet manager: snip import --from pet ~/.config/pet/snippet.toml",
+			Action:  Action(snippetCli.Import),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "from",
+					Value: "snip",
+					Usage: "snip, pet, navi, tldr, vscode or espanso",
+				},
+				cli.StringFlag{
+					Name:  "on-conflict",
+					Value: "skip",
+					Usage: "when the keyword is already saved: skip, overwrite, rename, keep-newer or interactive",
+				},
+				cli.StringFlag{
+					Name:  "suffix",
+					Value: "imported",
+					Usage: "suffix of the renamed keywords with --on-conflict rename",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "preview the changes without saving anything",
+				},
+				cli.BoolFlag{
+					Name:  "force, f",
+					Usage: "save the changes without confirmation",
+				},
+			},
+		},
+		{
+			Name:   "suggest",
+			Usage:  "suggest snippets from the commands of the shell history: snip suggest",
+			Action: Action(snippetCli.Suggest),
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "history",
+					Usage: "bash, zsh or fish history file, the ones of the home directory by default",
+				},
+				cli.IntFlag{
+					Name:  "min-count",
+					Value: 3,
+					Usage: "how many times similar commands have to be run to be suggested",
+				},
+				cli.IntFlag{
+					Name:  "long",
+					Value: 60,
+					Usage: "length from which a command is suggested even if it was run less",
+				},
+				cli.IntFlag{
+					Name:  "limit, n",
+					Value: 10,
+					Usage: "show at most n suggestions, 0 to show all",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "only show the suggestions",
+				},
+			},
+		},
+		{
+			Name:   "which",
+			Usage:  "find the snippet generating a command and the values of its placeholders: snip which \"lsof -i :8000\"",
+			Action: Action(snippetCli.Which),
+			Flags: append([]cli.Flag{
+				cli.BoolFlag{
+					Name:  "reveal",
+					Usage: "include the sensitive snippets",
+				},
+			}, formatFlags...),
+		},
+		{
+			Name:  "lib",
+			Usage: "manage the named libraries: snip lib create work",
+			Subcommands: []cli.Command{
+				{
+					Name:   "list",
+					Usage:  "list the libraries, * marks the default one",
+					Action: Action(snippetCli.LibList),
+					Flags:  formatFlags,
+				},
+				{
+					Name:   "create",
+					Usage:  "create a library: snip lib create work",
+					Action: Action(snippetCli.LibCreate),
+				},
+				{
+					Name:   "delete",
+					Usage:  "delete a library and its snippets: snip lib delete work",
+					Action: Action(snippetCli.LibDelete),
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "force, f",
+							Usage: "skip the confirmation",
+						},
+					},
+				},
+				{
+					Name:   "default",
+					Usage:  "print the default library, or set it: snip lib default work",
+					Action: Action(snippetCli.LibDefault),
+				},
+				{
+					Name:         "move",
+					Aliases:      []string{"mv"},
+					Usage:        "move a snippet to another library: snip lib move port work",
+					Action:       Action(snippetCli.LibMove),
+					BashComplete: snippetCli.Autocomplete,
+				},
+				{
+					Name:         "copy",
+					Aliases:      []string{"cp"},
+					Usage:        "copy a snippet to another library: snip --lib work lib copy port personal",
+					Action:       Action(snippetCli.LibCopy),
+					BashComplete: snippetCli.Autocomplete,
+				},
+			},
+		},
+		{
+			Name:  "config",
+			Usage: "show or change the settings of ~/.config/snip/config.toml: snip config set search substring",
+			Subcommands: []cli.Command{
+				{
+					Name:   "list",
+					Usage:  "list the settings with their value and where it comes from",
+					Action: Action(snippetCli.ConfigList),
+					Flags:  formatFlags,
+				},
+				{
+					Name:   "get",
+					Usage:  "print the value of a setting: snip config get shell",
+					Action: Action(snippetCli.ConfigGet),
+				},
+				{
+					Name:   "set",
+					Usage:  "save a setting, an empty value unsets it: snip config set confirm never",
+					Action: Action(snippetCli.ConfigSet),
+				},
+				{
+					Name:   "edit",
+					Usage:  "open the config file in the editor",
+					Action: Action(snippetCli.ConfigEdit),
+				},
+			},
+		},
+		{
+			Name:  "profile",
+			Usage: "bind placeholders to values per environment: snip profile set prod host=db.prod ns=web",
+			Subcommands: []cli.Command{
+				{
+					Name:   "list",
+					Usage:  "list the profiles, * marks the active one",
+					Action: Action(snippetCli.ProfileList),
+					Flags:  formatFlags,
+				},
+				{
+					Name:   "show",
+					Usage:  "show the values of a profile, the active one by default: snip profile show prod",
+					Action: Action(snippetCli.ProfileShow),
+					Flags:  formatFlags,
+				},
+				{
+					Name:   "set",
+					Usage:  "save placeholder values to a profile, name= removes one: snip profile set prod host=db.prod",
+					Action: Action(snippetCli.ProfileSet),
+				},
+				{
+					Name:   "delete",
+					Usage:  "delete a profile: snip profile delete staging",
+					Action: Action(snippetCli.ProfileDelete),
+				},
+				{
+					Name:   "use",
+					Usage:  "print the active profile, or activate one: snip profile use prod",
+					Action: Action(snippetCli.ProfileUse),
+				},
+			},
+		},
+		{
+			Name:  "secret",
+			Usage: "store the values of the {name:secret} placeholders in the encrypted vault: snip secret set token",
+			Subcommands: []cli.Command{
+				{
+					Name:   "list",
+					Usage:  "list the names of the secrets of the vault",
+					Action: Action(snippetCli.SecretList),
+					Flags:  formatFlags,
+				},
+				{
+					Name:   "set",
+					Usage:  "save a secret to the vault, its value is asked: snip secret set prod/token",
+					Action: Action(snippetCli.SecretSet),
+				},
+				{
+					Name:   "delete",
+					Usage:  "delete a secret from the vault: snip secret delete prod/token",
+					Action: Action(snippetCli.SecretDelete),
+				},
+			},
+		},
+		{
+			Name:   "encrypt",
+			Usage:  "encrypt the snippets file of the library with a passphrase, or one snippet: snip encrypt aws-login",
+			Action: Action(snippetCli.Encrypt),
+		},
+		{
+			Name:   "decrypt",
+			Usage:  "save the snippets file of the library, or one snippet, decrypted: snip decrypt aws-login",
+			Action: Action(snippetCli.Decrypt),
+		},
+		{
+			Name:  "unlock",
+			Usage: "ask the passphrase of the encrypted snippets once and keep it for a while: snip unlock --timeout 1h",
+			Flags: []cli.Flag{
+				cli.DurationFlag{
+					Name:  "timeout",
+					Usage: "how long the passphrase is kept, the unlock_timeout setting by default",
+				},
+			},
+			Action: Action(snippetCli.Unlock),
+		},
+		{
+			Name:   "lock",
+			Usage:  "forget the passphrase kept by snip unlock",
+			Action: Action(snippetCli.Lock),
+		},
 		{
 			Name:         "remove",
 			Aliases:      []string{"r"},
@@ -92,6 +624,32 @@ func main() {
 			Action:       Action(snippetCli.Remove),
 			BashComplete: snippetCli.Autocomplete,
 		},
+		{
+			Name:   "unlock-agent",
+			Hidden: true,
+			Action: snippetCli.UnlockAgent,
+		},
+		{
+			Name:   "restore-clipboard",
+			Hidden: true,
+			Action: snippetCli.RestoreClipboard,
+		},
+	}
+
+	// --lib, --file and --profile are accepted after every command as well, e.g. snip list --lib work
+	for i := range app.Commands {
+		if !app.Commands[i].Hidden {
+			app.Commands[i].Flags = append(app.Commands[i].Flags, libFlag, fileFlag, profileFlag)
+		}
+
+		if len(app.Commands[i].Subcommands) == 0 {
+			app.Commands[i].Before = initLibrary
+		}
+
+		for j := range app.Commands[i].Subcommands {
+			app.Commands[i].Subcommands[j].Flags = append(app.Commands[i].Subcommands[j].Flags, libFlag, fileFlag, profileFlag)
+			app.Commands[i].Subcommands[j].Before = initLibrary
+		}
 	}
 
 	app.Run(os.Args)
@@ -0,0 +1,31 @@
+package profile
+
+import "fmt"
+
+// InvalidNameError error when the profile name is not made of letters, digits, - and _
+type InvalidNameError struct {
+	Name string
+}
+
+func (e InvalidNameError) Error() string {
+	return fmt.Sprintf("Invalid profile name '%s', use letters, digits, - and _", e.Name)
+}
+
+// NotFoundError error when the profile does not exist
+type NotFoundError struct {
+	Name string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("Profile %s does not exist, create it with: snip profile set %s name=value", e.Name, e.Name)
+}
+
+// FileError error when the profiles file cannot be read
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("Invalid profiles file %s: %s", e.File, e.Err.Error())
+}
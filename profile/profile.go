@@ -0,0 +1,130 @@
+package profile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"github.com/baopham/snip/snippet"
+)
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Profiles binds placeholder names to values by profile name, e.g. prod: host=db.prod ns=web
+type Profiles map[string]map[string]string
+
+// File returns the file of the profiles, in the snippet directory
+func File() (string, error) {
+	dir, err := snippet.SnippetDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "profiles.toml"), nil
+}
+
+// CheckName returns an error unless the profile name is made of letters, digits, - and _
+func CheckName(name string) error {
+	if !nameRegexp.MatchString(name) {
+		return InvalidNameError{Name: name}
+	}
+
+	return nil
+}
+
+// Load reads the profiles of the file, none when there is no file
+func Load(file string) (Profiles, error) {
+	profiles := make(Profiles)
+	content, err := ioutil.ReadFile(file)
+
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := toml.Decode(string(content), &profiles); err != nil {
+		return nil, FileError{File: file, Err: err}
+	}
+
+	return profiles, nil
+}
+
+// Save writes the profiles to the file, readable by the user only as the values may be credentials
+func (p Profiles) Save(file string) error {
+	var buffer bytes.Buffer
+
+	if err := toml.NewEncoder(&buffer).Encode(p); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, buffer.Bytes(), snippet.FileMode)
+}
+
+// Names returns the names of the profiles in alphabetical order
+func (p Profiles) Names() []string {
+	names := make([]string, 0, len(p))
+
+	for name := range p {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Get returns the values of the profile
+func (p Profiles) Get(name string) (map[string]string, error) {
+	values, ok := p[name]
+
+	if !ok {
+		return nil, NotFoundError{Name: name}
+	}
+
+	return values, nil
+}
+
+// Set adds the values to the profile, creating it if needed. An empty value removes the placeholder
+func (p Profiles) Set(name string, values map[string]string) error {
+	if err := CheckName(name); err != nil {
+		return err
+	}
+
+	if p[name] == nil {
+		p[name] = make(map[string]string)
+	}
+
+	for k, v := range values {
+		if v == "" {
+			delete(p[name], k)
+			continue
+		}
+
+		p[name][k] = v
+	}
+
+	return nil
+}
+
+// Delete removes the profile
+func (p Profiles) Delete(name string) error {
+	if _, ok := p[name]; !ok {
+		return NotFoundError{Name: name}
+	}
+
+	delete(p, name)
+
+	return nil
+}
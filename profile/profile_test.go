@@ -0,0 +1,69 @@
+package profile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/baopham/snip/profile"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profiles", func() {
+	var dir, file string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-profile")
+		Expect(err).To(BeNil())
+
+		file = filepath.Join(dir, "profiles.toml")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should save and load the profiles", func() {
+		profiles, err := Load(file)
+
+		Expect(err).To(BeNil())
+		Expect(profiles).To(BeEmpty())
+
+		Expect(profiles.Set("prod", map[string]string{"host": "db.prod", "ns": "web"})).To(BeNil())
+		Expect(profiles.Set("dev", map[string]string{"host": "localhost"})).To(BeNil())
+		Expect(profiles.Set("prod", map[string]string{"ns": "", "user": "admin"})).To(BeNil())
+		Expect(profiles.Set("prod env", map[string]string{"host": "x"})).To(Equal(InvalidNameError{Name: "prod env"}))
+		Expect(profiles.Save(file)).To(BeNil())
+
+		info, err := os.Stat(file)
+		Expect(err).To(BeNil())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		loaded, err := Load(file)
+
+		Expect(err).To(BeNil())
+		Expect(loaded.Names()).To(Equal([]string{"dev", "prod"}))
+
+		values, err := loaded.Get("prod")
+
+		Expect(err).To(BeNil())
+		Expect(values).To(Equal(map[string]string{"host": "db.prod", "user": "admin"}))
+
+		Expect(loaded.Delete("dev")).To(BeNil())
+		Expect(loaded.Delete("dev")).To(Equal(NotFoundError{Name: "dev"}))
+
+		_, err = loaded.Get("dev")
+		Expect(err).To(Equal(NotFoundError{Name: "dev"}))
+	})
+
+	It("should report an invalid file", func() {
+		Expect(ioutil.WriteFile(file, []byte("[prod\nhost = 1\n"), 0600)).To(BeNil())
+
+		_, err := Load(file)
+
+		Expect(err).To(BeAssignableToTypeOf(FileError{}))
+	})
+})
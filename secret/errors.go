@@ -0,0 +1,52 @@
+package secret
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownProviderError error when the secret provider does not exist
+type UnknownProviderError struct {
+	Name string
+}
+
+func (e UnknownProviderError) Error() string {
+	return "Unknown secret provider " + e.Name + ", use some of: " + strings.Join(Providers, ", ")
+}
+
+// MissingCommandError error when the command provider is used without a command
+type MissingCommandError struct{}
+
+func (e MissingCommandError) Error() string {
+	return "Please set the command printing the secrets: snip config set secret_command 'op read op://dev/{name}/password'"
+}
+
+// ProviderError error when the provider fails to look up the secret
+type ProviderError struct {
+	Provider string
+	Name     string
+	Message  string
+}
+
+func (e ProviderError) Error() string {
+	return fmt.Sprintf("Could not read the secret %s from %s: %s", e.Name, e.Provider, e.Message)
+}
+
+// NotFoundError error when the secret is not in the vault
+type NotFoundError struct {
+	Name string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("Secret %s is not in the vault", e.Name)
+}
+
+// VaultError error when the vault cannot be opened
+type VaultError struct {
+	File string
+	Err  error
+}
+
+func (e VaultError) Error() string {
+	return fmt.Sprintf("Could not open the vault %s: %s", e.File, e.Err.Error())
+}
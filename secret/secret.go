@@ -0,0 +1,159 @@
+package secret
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+const (
+	ENV     = "env"
+	VAULT   = "vault"
+	PASS    = "pass"
+	COMMAND = "command"
+)
+
+// Providers lists the names of the secret providers
+var Providers = []string{ENV, VAULT, PASS, COMMAND}
+
+// ENV_PREFIX starts the environment variables of the secrets, e.g. SNIP_SECRET_TOKEN for {token:secret}
+const ENV_PREFIX = "SNIP_SECRET_"
+
+var envRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Provider looks up the value of a secret placeholder by name
+type Provider interface {
+	Name() string
+	// Lookup returns the value of the secret, false when the provider does not have it
+	Lookup(name string) (string, bool, error)
+}
+
+// Options configures the providers
+type Options struct {
+	// Shell runs the command of the command provider
+	Shell string
+	// Command prints the secret, {name} is replaced by the name of the secret, e.g. op read op://dev/{name}/password
+	Command string
+	// PassPrefix is the directory of the secrets in the password store
+	PassPrefix string
+	// Vault is the encrypted vault of the vault provider
+	Vault *Vault
+}
+
+// New returns the secret provider by name
+func New(name string, options Options) (Provider, error) {
+	switch strings.TrimSpace(name) {
+	case ENV:
+		return Env{Getenv: os.Getenv}, nil
+	case VAULT:
+		return options.Vault, nil
+	case PASS:
+		return Pass{Prefix: options.PassPrefix}, nil
+	case COMMAND:
+		if strings.TrimSpace(options.Command) == "" {
+			return nil, MissingCommandError{}
+		}
+
+		return Command{Shell: options.Shell, Command: options.Command}, nil
+	}
+
+	return nil, UnknownProviderError{Name: name}
+}
+
+// Env reads the secrets from the environment variables, SNIP_SECRET_DB_PASSWORD for {db-password:secret}
+type Env struct {
+	Getenv func(string) string
+}
+
+func (e Env) Name() string {
+	return ENV
+}
+
+func (e Env) Lookup(name string) (string, bool, error) {
+	value := e.Getenv(EnvName(name))
+	return value, value != "", nil
+}
+
+// EnvName returns the environment variable of the secret
+func EnvName(name string) string {
+	return ENV_PREFIX + strings.ToUpper(strings.Trim(envRegexp.ReplaceAllString(name, "_"), "_"))
+}
+
+// Pass reads the first line of the secrets of the pass password store, pass show snip/token for {token:secret}
+type Pass struct {
+	Prefix string
+}
+
+func (p Pass) Name() string {
+	return PASS
+}
+
+func (p Pass) Lookup(name string) (string, bool, error) {
+	entry := name
+
+	if p.Prefix != "" {
+		entry = strings.TrimSuffix(p.Prefix, "/") + "/" + name
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("pass", "show", entry)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	if _, ok := err.(*exec.ExitError); ok {
+		if strings.Contains(stderr.String(), "not in the password store") {
+			return "", false, nil
+		}
+
+		return "", false, ProviderError{Provider: PASS, Name: name, Message: strings.TrimSpace(stderr.String())}
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	value := strings.SplitN(string(out), "\n", 2)[0]
+
+	return value, value != "", nil
+}
+
+// Command runs a command printing the secret, a failure or an empty output means it does not have the secret
+type Command struct {
+	Shell   string
+	Command string
+}
+
+func (c Command) Name() string {
+	return COMMAND
+}
+
+func (c Command) Lookup(name string) (string, bool, error) {
+	shell := c.Shell
+
+	if shell == "" {
+		shell = "sh"
+	}
+
+	// {name} becomes the quoted $SNIP_SECRET_NAME set in the environment, the name is not interpreted by the shell
+	command := strings.Replace(c.Command, "{name}", `"$SNIP_SECRET_NAME"`, -1)
+
+	cmd := exec.Command(shell, "-c", command)
+	cmd.Env = append(os.Environ(), "SNIP_SECRET_NAME="+name)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+
+	if _, ok := err.(*exec.ExitError); ok {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	value := strings.TrimRight(string(out), "\r\n")
+
+	return value, value != "", nil
+}
@@ -0,0 +1,143 @@
+package secret_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/baopham/snip/crypt"
+	. "github.com/baopham/snip/secret"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Providers", func() {
+	It("should read the secrets from the environment", func() {
+		env := Env{Getenv: func(name string) string {
+			return map[string]string{"SNIP_SECRET_PROD_DB_PASSWORD": "s3cret"}[name]
+		}}
+
+		value, ok, err := env.Lookup("prod/db-password")
+
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("s3cret"))
+
+		_, ok, _ = env.Lookup("token")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should read the secrets printed by the command", func() {
+		command := Command{Command: `test {name} = token && echo "value of {name}"`}
+
+		value, ok, err := command.Lookup("token")
+
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("value of token"))
+
+		_, ok, err = command.Lookup("other")
+
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not let the name run in the shell", func() {
+		value, ok, err := Command{Command: "echo {name}"}.Lookup("$(echo injected)")
+
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("$(echo injected)"))
+	})
+
+	It("should create the providers by name", func() {
+		_, err := New("command", Options{})
+		Expect(err).To(Equal(MissingCommandError{}))
+
+		_, err = New("keychain", Options{})
+		Expect(err).To(Equal(UnknownProviderError{Name: "keychain"}))
+
+		provider, err := New(" env", Options{})
+		Expect(err).To(BeNil())
+		Expect(provider.Name()).To(Equal(ENV))
+	})
+})
+
+var _ = Describe("Vault", func() {
+	var dir, file string
+	var asked []bool
+
+	passphrase := func(value string) crypt.Passphrase {
+		return func(check func(string) error) (string, error) {
+			asked = append(asked, check == nil)
+
+			if check != nil {
+				return value, check(value)
+			}
+
+			return value, nil
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-secret")
+		Expect(err).To(BeNil())
+
+		file = filepath.Join(dir, "vault.enc")
+		asked = nil
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should not ask the passphrase while the vault does not exist", func() {
+		vault := &Vault{File: file, Passphrase: passphrase("passphrase")}
+
+		_, ok, err := vault.Lookup("token")
+
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeFalse())
+		Expect(asked).To(BeEmpty())
+	})
+
+	It("should save the secrets encrypted", func() {
+		vault := &Vault{File: file, Passphrase: passphrase("passphrase")}
+
+		Expect(vault.Set("token", "s3cret")).To(BeNil())
+		Expect(vault.Set("prod/token", "pr0d")).To(BeNil())
+		Expect(asked).To(Equal([]bool{true}))
+
+		data, err := ioutil.ReadFile(file)
+
+		Expect(err).To(BeNil())
+		Expect(crypt.IsEncrypted(data)).To(BeTrue())
+		Expect(string(data)).NotTo(ContainSubstring("s3cret"))
+
+		info, err := os.Stat(file)
+		Expect(err).To(BeNil())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		opened := &Vault{File: file, Passphrase: passphrase("passphrase")}
+
+		value, ok, err := opened.Lookup("prod/token")
+
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("pr0d"))
+		Expect(opened.Names()).To(Equal([]string{"prod/token", "token"}))
+
+		Expect(opened.Delete("token")).To(BeNil())
+		Expect(opened.Delete("token")).To(Equal(NotFoundError{Name: "token"}))
+	})
+
+	It("should refuse a wrong passphrase", func() {
+		Expect((&Vault{File: file, Passphrase: passphrase("passphrase")}).Set("token", "s3cret")).To(BeNil())
+
+		_, _, err := (&Vault{File: file, Passphrase: passphrase("wrong")}).Lookup("token")
+
+		Expect(err).To(Equal(VaultError{File: file, Err: crypt.WrongPassphraseError{}}))
+	})
+})
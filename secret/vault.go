@@ -0,0 +1,159 @@
+package secret
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/baopham/snip/crypt"
+	"github.com/baopham/snip/snippet"
+)
+
+// Vault is a file of secrets encrypted with a passphrase
+type Vault struct {
+	File string
+	// Passphrase returns the passphrase opening the vault, a new one when the vault does not exist yet
+	Passphrase crypt.Passphrase
+
+	secrets    map[string]string
+	passphrase string
+}
+
+// VaultFile returns the file of the vault, in the snippet directory
+func VaultFile() (string, error) {
+	dir, err := snippet.SnippetDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "vault.enc"), nil
+}
+
+func (v *Vault) Name() string {
+	return VAULT
+}
+
+// Lookup returns the secret of the vault, the passphrase is only asked when the vault exists
+func (v *Vault) Lookup(name string) (string, bool, error) {
+	if err := v.load(); err != nil {
+		return "", false, err
+	}
+
+	value, ok := v.secrets[name]
+
+	return value, ok, nil
+}
+
+// Names returns the names of the secrets in alphabetical order
+func (v *Vault) Names() ([]string, error) {
+	if err := v.load(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(v.secrets))
+
+	for name := range v.secrets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Set saves the secret in the vault, the vault is created if needed
+func (v *Vault) Set(name, value string) error {
+	if err := v.load(); err != nil {
+		return err
+	}
+
+	v.secrets[name] = value
+
+	return v.save()
+}
+
+// Delete removes the secret from the vault
+func (v *Vault) Delete(name string) error {
+	if err := v.load(); err != nil {
+		return err
+	}
+
+	if _, ok := v.secrets[name]; !ok {
+		return NotFoundError{Name: name}
+	}
+
+	delete(v.secrets, name)
+
+	return v.save()
+}
+
+func (v *Vault) load() error {
+	if v.secrets != nil {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(v.File)
+
+	if os.IsNotExist(err) {
+		v.secrets = make(map[string]string)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+
+	passphrase, err := v.Passphrase(func(passphrase string) error {
+		content, err = crypt.Decrypt(data, passphrase)
+		return err
+	})
+
+	if err != nil {
+		return VaultError{File: v.File, Err: err}
+	}
+
+	secrets := make(map[string]string)
+
+	if err := json.Unmarshal(content, &secrets); err != nil {
+		return VaultError{File: v.File, Err: err}
+	}
+
+	v.secrets, v.passphrase = secrets, passphrase
+
+	return nil
+}
+
+func (v *Vault) save() error {
+	if v.passphrase == "" {
+		passphrase, err := v.Passphrase(nil)
+
+		if err != nil {
+			return err
+		}
+
+		v.passphrase = passphrase
+	}
+
+	content, err := json.Marshal(v.secrets)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := crypt.Encrypt(content, v.passphrase)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(v.File), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(v.File, data, snippet.FileMode)
+}
@@ -0,0 +1,232 @@
+package snippet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baopham/snip/crypt"
+)
+
+// ENCRYPTED_PREFIX starts the content of the encrypted snippets in the snippets file, followed by the base64 encrypted content
+const ENCRYPTED_PREFIX = "snip-encrypted:"
+
+// Passphrase returns the passphrase of the encrypted snippet files and snippets, set by the command line to ask it
+var Passphrase crypt.Passphrase
+
+// IsEncryptedFile tells whether the whole snippets file is encrypted
+func IsEncryptedFile(filePath string) (bool, error) {
+	data, err := ioutil.ReadFile(filePath)
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return crypt.IsEncrypted(data), err
+}
+
+// EncryptedData returns the first data encrypted in the files: an encrypted snippets file, the content
+// of an encrypted snippet or any other encrypted file, nil when none is encrypted
+func EncryptedData(files ...string) ([]byte, error) {
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil || crypt.IsEncrypted(data) {
+			return data, err
+		}
+
+		snippets, err := Parse(bytes.NewReader(data))
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, snippet := range snippets {
+			if !snippet.Encrypted || !strings.HasPrefix(snippet.Content, ENCRYPTED_PREFIX) {
+				continue
+			}
+
+			data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(snippet.Content, ENCRYPTED_PREFIX))
+
+			if err != nil {
+				return nil, crypt.CorruptedError{}
+			}
+
+			return data, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// EncryptFile encrypts the whole snippets file
+func EncryptFile(filePath string) error {
+	data, encrypted, err := load(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	if encrypted {
+		return AlreadyEncryptedError{Name: filePath}
+	}
+
+	return store(filePath, data, true)
+}
+
+// DecryptFile saves the snippets file decrypted
+func DecryptFile(filePath string) error {
+	data, encrypted, err := load(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	if !encrypted {
+		return NotEncryptedError{Name: filePath}
+	}
+
+	return store(filePath, data, false)
+}
+
+// load returns the content of the snippets file, decrypted when the file is encrypted
+func load(filePath string) ([]byte, bool, error) {
+	data, err := ioutil.ReadFile(filePath)
+
+	if err != nil || !crypt.IsEncrypted(data) {
+		return data, false, err
+	}
+
+	content, err := decrypt(filePath, data)
+
+	return content, true, err
+}
+
+// store writes the content of the snippets file, encrypted if asked
+func store(filePath string, content []byte, encrypted bool) error {
+	if encrypted {
+		var err error
+
+		if content, err = encrypt(content); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filePath, content, FileMode)
+}
+
+// readRows returns the rows of the snippets file, whether it is encrypted
+func readRows(filePath string) ([][]string, bool, error) {
+	data, encrypted, err := load(filePath)
+
+	if err != nil {
+		return nil, encrypted, err
+	}
+
+	rows, err := newReader(bytes.NewReader(data)).ReadAll()
+
+	return rows, encrypted, err
+}
+
+// writeRows replaces the rows of the snippets file, encrypted if asked
+func writeRows(filePath string, rows [][]string, encrypted bool) error {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return store(filePath, buf.Bytes(), encrypted)
+}
+
+// row returns the CSV row of the snippet with its content encrypted if the snippet is encrypted
+func (s *Snippet) row() ([]string, error) {
+	row := s.toRow()
+
+	// the content of the snippets read without being decrypted, e.g. by Parse, is already encrypted
+	if !s.Encrypted || strings.HasPrefix(s.Content, ENCRYPTED_PREFIX) {
+		return row, nil
+	}
+
+	data, err := encrypt([]byte(s.Content))
+
+	if err != nil {
+		return nil, err
+	}
+
+	row[1] = ENCRYPTED_PREFIX + base64.StdEncoding.EncodeToString(data)
+
+	return row, nil
+}
+
+// decrypt replaces the content of the snippet read encrypted from the snippets file by its decrypted content
+func (s *Snippet) decrypt() error {
+	if !s.Encrypted || !strings.HasPrefix(s.Content, ENCRYPTED_PREFIX) {
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s.Content, ENCRYPTED_PREFIX))
+
+	if err != nil {
+		return crypt.CorruptedError{}
+	}
+
+	content, err := decrypt(s.Keyword, data)
+
+	if err != nil {
+		return err
+	}
+
+	s.Content = string(content)
+
+	return nil
+}
+
+func encrypt(content []byte) ([]byte, error) {
+	if Passphrase == nil {
+		return nil, LockedError{}
+	}
+
+	passphrase, err := Passphrase(nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return crypt.Encrypt(content, passphrase)
+}
+
+// decrypt decrypts the data of the snippets file or snippet named name with the passphrase
+func decrypt(name string, data []byte) ([]byte, error) {
+	if Passphrase == nil {
+		return nil, LockedError{Name: name}
+	}
+
+	var content []byte
+
+	_, err := Passphrase(func(passphrase string) error {
+		var err error
+		content, err = crypt.Decrypt(data, passphrase)
+		return err
+	})
+
+	if err != nil {
+		return nil, DecryptError{Name: name, Err: err}
+	}
+
+	return content, nil
+}
@@ -2,6 +2,7 @@ package snippet
 
 import (
 	"fmt"
+	"strings"
 )
 
 // SnippetAlreadyExistError error when the snippet already exists
@@ -12,3 +13,119 @@ type SnippetAlreadyExistError struct {
 func (e SnippetAlreadyExistError) Error() string {
 	return fmt.Sprintf("Snippet %s already exists", e.Keyword)
 }
+
+// SnippetNotFoundError error when the snippet to update does not exist
+type SnippetNotFoundError struct {
+	Keyword string
+}
+
+func (e SnippetNotFoundError) Error() string {
+	return fmt.Sprintf("Snippet %s does not exist", e.Keyword)
+}
+
+// IncludeCycleError error when snippets include each other
+type IncludeCycleError struct {
+	Keywords []string
+}
+
+func (e IncludeCycleError) Error() string {
+	return fmt.Sprintf("Snippets include each other: %s", strings.Join(e.Keywords, " -> "))
+}
+
+// MissingIncludeError error when the included snippet does not exist
+type MissingIncludeError struct {
+	Keyword string
+	Include string
+}
+
+func (e MissingIncludeError) Error() string {
+	return fmt.Sprintf("Snippet %s includes %s which does not exist", e.Keyword, e.Include)
+}
+
+// InvalidLibraryNameError error when the library name is not made of letters, digits, - and _
+type InvalidLibraryNameError struct {
+	Name string
+}
+
+func (e InvalidLibraryNameError) Error() string {
+	return fmt.Sprintf("Invalid library name '%s', use letters, digits, - and _", e.Name)
+}
+
+// LibraryAlreadyExistError error when the library to create already exists
+type LibraryAlreadyExistError struct {
+	Name string
+}
+
+func (e LibraryAlreadyExistError) Error() string {
+	return fmt.Sprintf("Library %s already exists", e.Name)
+}
+
+// LibraryNotFoundError error when the library was not created
+type LibraryNotFoundError struct {
+	Name string
+}
+
+func (e LibraryNotFoundError) Error() string {
+	return fmt.Sprintf("Library %s does not exist, create it with: snip lib create %s", e.Name, e.Name)
+}
+
+// DefaultLibraryDeleteError error when deleting the default library
+type DefaultLibraryDeleteError struct {
+	Name string
+}
+
+func (e DefaultLibraryDeleteError) Error() string {
+	return fmt.Sprintf("Library %s is the default library and cannot be deleted", e.Name)
+}
+
+// MigrationError error when ~/.snip could not be moved to the XDG data directory
+type MigrationError struct {
+	From string
+	To   string
+	Err  error
+}
+
+func (e MigrationError) Error() string {
+	return fmt.Sprintf("Could not move %s to %s, still using %s: %s", e.From, e.To, e.From, e.Err.Error())
+}
+
+// AlreadyEncryptedError error when encrypting a snippets file or a snippet which is encrypted
+type AlreadyEncryptedError struct {
+	Name string
+}
+
+func (e AlreadyEncryptedError) Error() string {
+	return fmt.Sprintf("%s is already encrypted", e.Name)
+}
+
+// NotEncryptedError error when decrypting a snippets file or a snippet which is not encrypted
+type NotEncryptedError struct {
+	Name string
+}
+
+func (e NotEncryptedError) Error() string {
+	return fmt.Sprintf("%s is not encrypted", e.Name)
+}
+
+// LockedError error when no passphrase can be asked for the encrypted snippets
+type LockedError struct {
+	Name string
+}
+
+func (e LockedError) Error() string {
+	if e.Name == "" {
+		return "No passphrase to encrypt the snippets"
+	}
+
+	return fmt.Sprintf("%s is encrypted and no passphrase can be asked", e.Name)
+}
+
+// DecryptError error when the snippets file or the snippet cannot be decrypted
+type DecryptError struct {
+	Name string
+	Err  error
+}
+
+func (e DecryptError) Error() string {
+	return fmt.Sprintf("Could not decrypt %s: %s", e.Name, e.Err.Error())
+}
@@ -0,0 +1,116 @@
+package snippet
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// HOME_ENV is the environment variable overriding the directory of the snippets
+const HOME_ENV = "SNIP_HOME"
+
+// SnippetDir returns the directory of the snippets, the libraries and the history: $SNIP_HOME if set,
+// $XDG_DATA_HOME/snip otherwise, ~/.local/share/snip by default. ~/.snip is used until it is migrated by Init
+func SnippetDir() (string, error) {
+	if dir := os.Getenv(HOME_ENV); dir != "" {
+		return filepath.Abs(dir)
+	}
+
+	dir, err := dataDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	legacy, err := LegacyDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	if !exists(dir) && exists(legacy) {
+		return legacy, nil
+	}
+
+	return dir, nil
+}
+
+// SnippetFile returns the file of the default library
+func SnippetFile() (string, error) {
+	dir, err := SnippetDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "snippets.csv"), nil
+}
+
+// LegacyDir returns ~/.snip, the directory of the snippets before the XDG base directories were followed
+func LegacyDir() (string, error) {
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".snip"), nil
+}
+
+// Init moves ~/.snip to the XDG data directory, the snippet directory is created by the first write instead.
+// A failed migration is returned as a MigrationError, the legacy directory is used then
+func Init() error {
+	if os.Getenv(HOME_ENV) != "" {
+		return nil
+	}
+
+	return migrate()
+}
+
+// migrate moves ~/.snip to the data directory unless the data directory exists already
+func migrate() error {
+	legacy, err := LegacyDir()
+
+	if err != nil {
+		return err
+	}
+
+	dir, err := dataDir()
+
+	if err != nil {
+		return err
+	}
+
+	if !exists(legacy) || exists(dir) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return MigrationError{From: legacy, To: dir, Err: err}
+	}
+
+	if err := os.Rename(legacy, dir); err != nil {
+		return MigrationError{From: legacy, To: dir, Err: err}
+	}
+
+	return nil
+}
+
+// dataDir returns $XDG_DATA_HOME/snip, ~/.local/share/snip by default
+func dataDir() (string, error) {
+	if data := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(data) {
+		return filepath.Join(data, "snip"), nil
+	}
+
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".local", "share", "snip"), nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,64 @@
+package snippet
+
+import (
+	"regexp"
+)
+
+var includeRegexp = regexp.MustCompile(`\{@([^{}\s]+)\}`)
+
+// Expand returns a copy of the snippet whose includes are recursively replaced by the content
+// of the included snippets of the library. Their placeholders become the snippet's placeholders,
+// and the copy is sensitive or encrypted when any of them is
+func (s *Snippet) Expand(library *Library) (*Snippet, error) {
+	expanded := *s
+
+	content, err := s.expand(library, []string{s.Keyword}, &expanded)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expanded.Content = content
+
+	return &expanded, nil
+}
+
+func (s *Snippet) expand(library *Library, parents []string, expanded *Snippet) (string, error) {
+	var err error
+
+	content := includeRegexp.ReplaceAllStringFunc(s.Content, func(include string) string {
+		if err != nil {
+			return include
+		}
+
+		keyword := includeRegexp.FindStringSubmatch(include)[1]
+
+		for _, parent := range parents {
+			if parent == keyword {
+				err = IncludeCycleError{Keywords: append(append([]string{}, parents...), keyword)}
+				return include
+			}
+		}
+
+		var included *Snippet
+
+		if included, err = library.SearchExact(keyword); err != nil {
+			return include
+		}
+
+		if included == nil {
+			err = MissingIncludeError{Keyword: parents[len(parents)-1], Include: keyword}
+			return include
+		}
+
+		expanded.Sensitive = expanded.Sensitive || included.Sensitive
+		expanded.Encrypted = expanded.Encrypted || included.Encrypted
+
+		var content string
+		content, err = included.expand(library, append(parents[:len(parents):len(parents)], keyword), expanded)
+
+		return content
+	})
+
+	return content, err
+}
@@ -0,0 +1,177 @@
+package snippet
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DEFAULT_LIBRARY is the library of SnippetFile, the one used before the named libraries
+const DEFAULT_LIBRARY = "default"
+
+var libraryNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// LibrariesDir returns the directory of the named libraries, one CSV file per library
+func LibrariesDir() (string, error) {
+	dir, err := SnippetDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(dir, "libraries"), nil
+}
+
+// LibraryFile returns the snippet file of the named library, it may not exist
+func LibraryFile(name string) (string, error) {
+	if !libraryNameRegexp.MatchString(name) {
+		return "", InvalidLibraryNameError{Name: name}
+	}
+
+	if name == DEFAULT_LIBRARY {
+		return SnippetFile()
+	}
+
+	dir, err := LibrariesDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(dir, name+".csv"), nil
+}
+
+// Libraries returns the names of the libraries, the default library first
+func Libraries() ([]string, error) {
+	dir, err := LibrariesDir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(dir)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".csv")
+
+		if file.IsDir() || name == file.Name() || name == DEFAULT_LIBRARY || !libraryNameRegexp.MatchString(name) {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return append([]string{DEFAULT_LIBRARY}, names...), nil
+}
+
+// LibraryExists tells whether the named library was created
+func LibraryExists(name string) (bool, error) {
+	file, err := LibraryFile(name)
+
+	if err != nil {
+		return false, err
+	}
+
+	if name == DEFAULT_LIBRARY {
+		return true, nil
+	}
+
+	_, err = os.Stat(file)
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// CreateLibrary creates the empty snippet file of the named library and returns it
+func CreateLibrary(name string) (string, error) {
+	exists, err := LibraryExists(name)
+
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		return "", LibraryAlreadyExistError{Name: name}
+	}
+
+	file, err := LibraryFile(name)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(file, os.O_RDONLY|os.O_CREATE, FileMode)
+
+	if err != nil {
+		return "", err
+	}
+
+	return file, f.Close()
+}
+
+// DeleteLibrary deletes the named library with its snippets, the default library cannot be deleted
+func DeleteLibrary(name string) error {
+	if err := checkLibrary(name); err != nil {
+		return err
+	}
+
+	if name == DEFAULT_LIBRARY {
+		return DefaultLibraryDeleteError{Name: name}
+	}
+
+	file, err := LibraryFile(name)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(file)
+}
+
+// LibraryName returns the name of the library of the snippet file, empty for a project file
+func LibraryName(file string) string {
+	if global, err := SnippetFile(); err == nil && file == global {
+		return DEFAULT_LIBRARY
+	}
+
+	dir, err := LibrariesDir()
+
+	if err != nil || filepath.Dir(file) != dir || filepath.Ext(file) != ".csv" {
+		return ""
+	}
+
+	return strings.TrimSuffix(filepath.Base(file), ".csv")
+}
+
+func checkLibrary(name string) error {
+	exists, err := LibraryExists(name)
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return LibraryNotFoundError{Name: name}
+	}
+
+	return nil
+}
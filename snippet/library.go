@@ -0,0 +1,256 @@
+package snippet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/baopham/snip/crypt"
+)
+
+// projectFiles are the snippet files of a project, looked up from the current directory to the root of the repository
+var projectFiles = []string{path.Join(".snip", "snippets.csv"), "snippets.csv"}
+
+// Library is the snippet files in use by precedence: the project files nearest to the current directory first,
+// the global file last. A snippet shadows the snippets with the same keyword in the next files
+type Library struct {
+	Files []string
+	// Name of the named library used as the global file, empty when opened with OpenLibraryFile
+	Name string
+	// Root of the project, the directory of the repository or the current directory
+	Root string
+}
+
+// NewLibrary returns the library of the given files, by precedence
+func NewLibrary(files ...string) *Library {
+	return &Library{Files: files}
+}
+
+// OpenLibrary returns the library of the current directory: its project files and the file of the named library
+// as the global file
+func OpenLibrary(name string) (*Library, error) {
+	if err := checkLibrary(name); err != nil {
+		return nil, err
+	}
+
+	global, err := LibraryFile(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return openLibrary(global, name)
+}
+
+// OpenLibraryFile returns the library of the current directory with the given file as the global file
+func OpenLibraryFile(file string) (*Library, error) {
+	global, err := filepath.Abs(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return openLibrary(global, "")
+}
+
+func openLibrary(global, name string) (*Library, error) {
+	dir, err := os.Getwd()
+
+	if err != nil {
+		return nil, err
+	}
+
+	root, files := FindProjectFiles(dir)
+	library := &Library{Root: root, Name: name}
+
+	for _, file := range files {
+		if !sameFile(file, global) {
+			library.Files = append(library.Files, file)
+		}
+	}
+
+	library.Files = append(library.Files, global)
+
+	return library, nil
+}
+
+// FindProjectFiles walks up from the directory to the root of the repository, the directory having .git,
+// and returns the root with the project files found, nearest first. Out of a repository only the directory is searched
+func FindProjectFiles(dir string) (string, []string) {
+	root := dir
+
+	for current := dir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			root = current
+			break
+		}
+
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	var files []string
+
+	for current := dir; ; current = filepath.Dir(current) {
+		for _, name := range projectFiles {
+			file := filepath.Join(current, name)
+
+			if info, err := os.Stat(file); err == nil && !info.IsDir() {
+				files = append(files, file)
+			}
+		}
+
+		if current == root || filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	return root, files
+}
+
+// Global returns the global snippet file
+func (l *Library) Global() string {
+	return l.Files[len(l.Files)-1]
+}
+
+// Local returns the nearest project file, empty out of a project
+func (l *Library) Local() string {
+	if len(l.Files) < 2 {
+		return ""
+	}
+
+	return l.Files[0]
+}
+
+// LocalFile returns the nearest project file, .snip/snippets.csv at the root of the project is created if there is none
+func (l *Library) LocalFile() (string, error) {
+	if local := l.Local(); local != "" {
+		return local, nil
+	}
+
+	file := filepath.Join(l.Root, projectFiles[0])
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(file, os.O_RDONLY|os.O_CREATE, FileMode)
+
+	if err != nil {
+		return "", err
+	}
+
+	return file, f.Close()
+}
+
+// GetAll returns the snippets of every file but the shadowed ones, with their Source
+func (l *Library) GetAll() ([]*Snippet, error) {
+	var snippets []*Snippet
+
+	seen := make(map[string]bool)
+
+	for _, file := range l.Files {
+		found, err := GetAll(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, snippet := range found {
+			if seen[snippet.Keyword] {
+				continue
+			}
+
+			seen[snippet.Keyword] = true
+			snippet.Source = file
+			snippets = append(snippets, snippet)
+		}
+	}
+
+	return snippets, nil
+}
+
+// SearchExact returns the snippet with the keyword from the first file having it, nil if none has it.
+// Shadows tells the next file having the keyword, the snippet of a project file can replace a global one
+func (l *Library) SearchExact(keyword string) (*Snippet, error) {
+	for i, file := range l.Files {
+		snippet, err := SearchExact(keyword, file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if snippet == nil {
+			continue
+		}
+
+		snippet.Source = file
+
+		if snippet.Shadows, err = shadowed(keyword, l.Files[i+1:]); err != nil {
+			return nil, err
+		}
+
+		return snippet, nil
+	}
+
+	return nil, nil
+}
+
+// shadowed returns the first of the files having the keyword, without decrypting anything:
+// the encrypted files are not looked into
+func shadowed(keyword string, files []string) (string, error) {
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+
+		if os.IsNotExist(err) || crypt.IsEncrypted(data) {
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		snippets, err := Parse(bytes.NewReader(data))
+
+		if err != nil {
+			return "", err
+		}
+
+		for _, snippet := range snippets {
+			if snippet.Keyword == keyword {
+				return file, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// Search searches the snippets which are not shadowed, with SEARCH_FUZZY, SEARCH_SUBSTRING or SEARCH_EXACT
+func (l *Library) Search(searchTerm string, mode SearchCode) ([]*Snippet, error) {
+	snippets, err := l.GetAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*Snippet
+
+	for _, snippet := range snippets {
+		if snippet.matches(searchTerm, mode) {
+			found = append(found, snippet)
+		}
+	}
+
+	return found, nil
+}
+
+func sameFile(a, b string) bool {
+	aInfo, aErr := os.Stat(a)
+	bInfo, bErr := os.Stat(b)
+
+	return aErr == nil && bErr == nil && os.SameFile(aInfo, bInfo)
+}
@@ -0,0 +1,234 @@
+package snippet_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/baopham/snip/snippet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Library", func() {
+	var dir, project, global string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-library")
+		Expect(err).To(BeNil())
+
+		Expect(os.MkdirAll(filepath.Join(dir, "repo", ".git"), 0755)).To(BeNil())
+		Expect(os.MkdirAll(filepath.Join(dir, "repo", "app", ".snip"), 0755)).To(BeNil())
+
+		project = filepath.Join(dir, "repo", "app", ".snip", "snippets.csv")
+		global = filepath.Join(dir, "global.csv")
+
+		Expect(ioutil.WriteFile(project, []byte("port,lsof -i :{p},Project port\nmigrate,make migrate\n"), FileMode)).To(BeNil())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "repo", "snippets.csv"), []byte("test,make test\n"), FileMode)).To(BeNil())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "snippets.csv"), []byte("outside,echo out\n"), FileMode)).To(BeNil())
+		Expect(ioutil.WriteFile(global, []byte("port,lsof -i :{p} -P,Global port\nkctx,kubectl {@migrate}\n"), FileMode)).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Context("when calling FindProjectFiles()", func() {
+		It("should find the project files up to the root of the repository, nearest first", func() {
+			root, files := FindProjectFiles(filepath.Join(dir, "repo", "app"))
+
+			Expect(root).To(Equal(filepath.Join(dir, "repo")))
+			Expect(files).To(Equal([]string{project, filepath.Join(dir, "repo", "snippets.csv")}))
+		})
+	})
+
+	Context("when calling Search()", func() {
+		It("should match the snippets with the search mode", func() {
+			library := NewLibrary(project, global)
+
+			found, err := library.Search("lsi", SEARCH_FUZZY)
+			Expect(err).To(BeNil())
+			Expect(found).To(HaveLen(1))
+
+			found, err = library.Search("LSOF -I", SEARCH_SUBSTRING)
+			Expect(err).To(BeNil())
+			Expect(found).To(HaveLen(1))
+			Expect(found[0].Keyword).To(Equal("port"))
+
+			found, err = library.Search("lsi", SEARCH_SUBSTRING)
+			Expect(err).To(BeNil())
+			Expect(found).To(BeEmpty())
+
+			found, err = library.Search("kctx", SEARCH_EXACT)
+			Expect(err).To(BeNil())
+			Expect(found).To(HaveLen(1))
+		})
+	})
+
+	Context("when calling SearchExact()", func() {
+		It("should tell the snippet shadowed by the one of the project", func() {
+			library := NewLibrary(project, global)
+
+			snippet, err := library.SearchExact("port")
+			Expect(err).To(BeNil())
+			Expect(snippet.Source).To(Equal(project))
+			Expect(snippet.Shadows).To(Equal(global))
+
+			snippet, err = library.SearchExact("migrate")
+			Expect(err).To(BeNil())
+			Expect(snippet.Source).To(Equal(project))
+			Expect(snippet.Shadows).To(BeEmpty())
+		})
+	})
+
+	Context("when calling GetAll()", func() {
+		It("should shadow the snippets of the next files", func() {
+			library := NewLibrary(project, global)
+
+			snippets, err := library.GetAll()
+
+			Expect(err).To(BeNil())
+			Expect(snippets).To(HaveLen(3))
+			Expect(snippets[0].Description).To(Equal("Project port"))
+			Expect(snippets[0].Source).To(Equal(project))
+			Expect(snippets[2].Keyword).To(Equal("kctx"))
+			Expect(snippets[2].Source).To(Equal(global))
+		})
+	})
+
+	Context("when expanding a global snippet including a project snippet", func() {
+		It("should find the include in the library", func() {
+			library := NewLibrary(project, global)
+
+			snippet, err := library.SearchExact("kctx")
+			Expect(err).To(BeNil())
+
+			expanded, err := snippet.Expand(library)
+
+			Expect(err).To(BeNil())
+			Expect(expanded.Content).To(Equal("kubectl make migrate"))
+		})
+	})
+})
+
+var _ = Describe("Named libraries", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-home")
+		Expect(err).To(BeNil())
+		Expect(os.Setenv(HOME_ENV, dir)).To(BeNil())
+		Expect(Init()).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(HOME_ENV)
+		os.RemoveAll(dir)
+	})
+
+	It("should create, list and delete the libraries", func() {
+		file, err := CreateLibrary("work")
+
+		Expect(err).To(BeNil())
+		Expect(file).To(Equal(filepath.Join(dir, "libraries", "work.csv")))
+		Expect(LibraryName(file)).To(Equal("work"))
+
+		_, err = CreateLibrary("work")
+		Expect(err).To(Equal(LibraryAlreadyExistError{Name: "work"}))
+
+		_, err = CreateLibrary("../work")
+		Expect(err).To(Equal(InvalidLibraryNameError{Name: "../work"}))
+
+		names, err := Libraries()
+		Expect(err).To(BeNil())
+		Expect(names).To(Equal([]string{DEFAULT_LIBRARY, "work"}))
+
+		Expect(DeleteLibrary(DEFAULT_LIBRARY)).To(Equal(DefaultLibraryDeleteError{Name: DEFAULT_LIBRARY}))
+		Expect(DeleteLibrary("work")).To(BeNil())
+		Expect(DeleteLibrary("work")).To(Equal(LibraryNotFoundError{Name: "work"}))
+	})
+
+	It("should open the named library as the global file", func() {
+		_, err := CreateLibrary("work")
+		Expect(err).To(BeNil())
+
+		library, err := OpenLibrary("work")
+
+		Expect(err).To(BeNil())
+		Expect(library.Name).To(Equal("work"))
+		Expect(library.Global()).To(Equal(filepath.Join(dir, "libraries", "work.csv")))
+
+		library, err = OpenLibrary(DEFAULT_LIBRARY)
+
+		Expect(err).To(BeNil())
+		Expect(library.Global()).To(Equal(filepath.Join(dir, "snippets.csv")))
+
+		_, err = OpenLibrary("personal")
+		Expect(err).To(Equal(LibraryNotFoundError{Name: "personal"}))
+	})
+})
+
+var _ = Describe("Init", func() {
+	var home, data, user string
+
+	BeforeEach(func() {
+		var err error
+
+		home, err = ioutil.TempDir("", "snip-user")
+		Expect(err).To(BeNil())
+
+		data = filepath.Join(home, "data")
+		user = os.Getenv("HOME")
+
+		os.Unsetenv(HOME_ENV)
+		Expect(os.Setenv("HOME", home)).To(BeNil())
+		Expect(os.Setenv("XDG_DATA_HOME", data)).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.Setenv("HOME", user)
+		os.Unsetenv("XDG_DATA_HOME")
+		os.RemoveAll(home)
+	})
+
+	It("should create the XDG data directory on the first save", func() {
+		Expect(Init()).To(BeNil())
+
+		file, err := SnippetFile()
+
+		Expect(err).To(BeNil())
+		Expect(file).To(Equal(filepath.Join(data, "snip", "snippets.csv")))
+		Expect(filepath.Dir(file)).NotTo(BeADirectory())
+
+		snippet := Snippet{Keyword: "port", Content: "lsof -i :{p}"}
+
+		Expect(snippet.Save(file)).To(BeNil())
+		Expect(file).To(BeARegularFile())
+	})
+
+	It("should move ~/.snip to the XDG data directory", func() {
+		legacy := filepath.Join(home, ".snip")
+
+		Expect(os.MkdirAll(legacy, 0755)).To(BeNil())
+		Expect(ioutil.WriteFile(filepath.Join(legacy, "snippets.csv"), []byte("port,lsof -i :{p}\n"), FileMode)).To(BeNil())
+
+		dir, err := SnippetDir()
+		Expect(err).To(BeNil())
+		Expect(dir).To(Equal(legacy))
+
+		Expect(Init()).To(BeNil())
+
+		dir, err = SnippetDir()
+		Expect(err).To(BeNil())
+		Expect(dir).To(Equal(filepath.Join(data, "snip")))
+		Expect(legacy).NotTo(BeADirectory())
+
+		snippets, err := GetAll(filepath.Join(dir, "snippets.csv"))
+		Expect(err).To(BeNil())
+		Expect(snippets).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,79 @@
+package snippet
+
+import (
+	"regexp"
+	"strings"
+)
+
+var spacesRegexp = regexp.MustCompile(`\s+`)
+
+// Match tells if the command could have been generated by the snippet and returns the values of its placeholders,
+// e.g. lsof -i :8000 matches lsof -i :{p} with p=8000
+func (s *Snippet) Match(command string) (map[string]string, bool) {
+	var (
+		pattern strings.Builder
+		names   []string
+	)
+
+	content := strings.TrimSpace(s.Content)
+	last := 0
+
+	literal := func(text string) {
+		for i, part := range spacesRegexp.Split(text, -1) {
+			if i > 0 {
+				pattern.WriteString(`\s+`)
+			}
+
+			pattern.WriteString(regexp.QuoteMeta(part))
+		}
+	}
+
+	pattern.WriteString(`^`)
+
+	for _, match := range placeholderIndexes(content) {
+		literal(content[last:match[0]])
+		pattern.WriteString(`(.+?)`)
+		names = append(names, content[match[2]:match[3]])
+		last = match[1]
+	}
+
+	literal(content[last:])
+	pattern.WriteString(`$`)
+
+	re, err := regexp.Compile(pattern.String())
+
+	if err != nil {
+		return nil, false
+	}
+
+	groups := re.FindStringSubmatch(strings.TrimSpace(command))
+
+	if groups == nil {
+		return nil, false
+	}
+
+	values := make(map[string]string)
+
+	for i, name := range names {
+		// a placeholder used twice must have the same value
+		if value, ok := values[name]; ok && value != groups[i+1] {
+			return nil, false
+		}
+
+		values[name] = groups[i+1]
+	}
+
+	return values, true
+}
+
+// Literal returns the length of the content without its placeholders, the longer the more specific the snippet
+func (s *Snippet) Literal() int {
+	content := strings.TrimSpace(s.Content)
+	length := len(content)
+
+	for _, match := range placeholderIndexes(content) {
+		length -= match[1] - match[0]
+	}
+
+	return length
+}
@@ -0,0 +1,59 @@
+package snippet
+
+import (
+	"strings"
+)
+
+// Step is one step of a runbook: a command to execute or a note to read
+type Step struct {
+	Note    bool
+	Content string
+}
+
+// Snippet returns the step as a snippet of the runbook, to list and build its placeholders.
+// The step is sensitive or encrypted like the runbook
+func (step Step) Snippet(runbook *Snippet) *Snippet {
+	return &Snippet{
+		Keyword:     runbook.Keyword,
+		Description: runbook.Description,
+		Content:     step.Content,
+		Source:      runbook.Source,
+		Sensitive:   runbook.Sensitive,
+		Encrypted:   runbook.Encrypted,
+	}
+}
+
+// Steps returns the steps of a runbook. Each line of the content is a step,
+// lines starting with # are notes and a trailing \ continues a command on the next line
+func (s *Snippet) Steps() []Step {
+	var steps []Step
+	var command []string
+
+	for _, line := range strings.Split(s.Content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(command) == 0 && strings.HasPrefix(line, "#") {
+			steps = append(steps, Step{Note: true, Content: strings.TrimSpace(strings.TrimPrefix(line, "#"))})
+			continue
+		}
+
+		if len(command) == 0 && line == "" {
+			continue
+		}
+
+		if strings.HasSuffix(line, "\\") {
+			command = append(command, line)
+			continue
+		}
+
+		command = append(command, line)
+		steps = append(steps, Step{Content: strings.Join(command, "\n")})
+		command = nil
+	}
+
+	if len(command) > 0 {
+		steps = append(steps, Step{Content: strings.TrimSuffix(strings.Join(command, "\n"), "\\")})
+	}
+
+	return steps
+}
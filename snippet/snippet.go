@@ -1,13 +1,16 @@
 package snippet
 
 import (
+	"bytes"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"os"
-	"os/user"
-	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/baopham/snip/util"
 	"github.com/renstrom/fuzzysearch/fuzzy"
@@ -19,40 +22,93 @@ const (
 	SEARCH_EXACT     SearchCode = 1
 	SEARCH_FUZZY     SearchCode = 2
 	SEARCH_MATCH_ANY SearchCode = 3
+	SEARCH_SUBSTRING SearchCode = 4
 )
 
 type SearchCode int
 
+// SnippetType tells how the snippet content is used
+type SnippetType string
+
+const (
+	TYPE_COMMAND SnippetType = ""
+	TYPE_RUNBOOK SnippetType = "runbook"
+)
+
+var placeholderRegexp = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)(:secret)?\}`)
+
 // Snippet represents the snippet
 type Snippet struct {
 	Keyword     string
 	Description string
 	Content     string
+	Type        SnippetType
+	// Sensitive snippets are cleared from the clipboard and hidden from the listings
+	Sensitive bool
+	Tags      []string
+	// Encrypted snippets have their content encrypted in the snippets file, Content is decrypted when read
+	Encrypted bool
+	// Updated is when the snippet was last saved, zero for snippets saved before it was recorded
+	Updated time.Time
+	// Source is the file the snippet was read from by a Library
+	Source string
+	// Shadows is the next file of the Library having the keyword, set by Library.SearchExact
+	Shadows string
 }
 
-// Save snippet
+// Save snippet, the snippets file stays encrypted if it is
 func (s *Snippet) Save(filePath string) error {
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, FileMode)
+	existingSnippet, err := SearchExact(s.Keyword, filePath)
 
-	defer util.Check(file.Close)
+	if err != nil {
+		return err
+	}
+
+	if existingSnippet != nil {
+		return SnippetAlreadyExistError{Keyword: existingSnippet.Keyword}
+	}
+
+	if s.Updated.IsZero() {
+		s.Updated = now()
+	}
+
+	row, err := s.row()
 
 	if err != nil {
 		return err
 	}
 
-	existingSnippet, err := SearchExact(s.Keyword, filePath)
+	encrypted, err := IsEncryptedFile(filePath)
 
 	if err != nil {
 		return err
 	}
 
-	if existingSnippet != nil {
-		return SnippetAlreadyExistError{Keyword: existingSnippet.Keyword}
+	if encrypted {
+		rows, _, err := readRows(filePath)
+
+		if err != nil {
+			return err
+		}
+
+		return writeRows(filePath, append(rows, row), true)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, FileMode)
+
+	if err != nil {
+		return err
 	}
 
+	defer util.Check(file.Close)
+
 	w := csv.NewWriter(file)
 
-	if err := w.Write([]string{s.Keyword, s.Content, s.Description}); err != nil {
+	if err := w.Write(row); err != nil {
 		return err
 	}
 
@@ -63,49 +119,152 @@ func (s *Snippet) Save(filePath string) error {
 
 // Remove a saved snippet
 func (s *Snippet) Remove(filePath string) error {
-	rows := make([][]string, 0)
+	_, err := rewrite(filePath, func(row []string) []string {
+		if s.Keyword == fromRow(row).Keyword {
+			return nil
+		}
+
+		return row
+	})
+
+	return err
+}
+
+// Update replaces the saved snippet having the same keyword
+func (s *Snippet) Update(filePath string) error {
+	if s.Updated.IsZero() {
+		s.Updated = now()
+	}
 
-	file, err := os.Open(filePath)
+	updated, err := s.row()
 
 	if err != nil {
 		return err
 	}
 
-	csvr := csv.NewReader(file)
+	found, err := rewrite(filePath, func(row []string) []string {
+		if s.Keyword == fromRow(row).Keyword {
+			return updated
+		}
 
-	for {
-		row, err := csvr.Read()
+		return row
+	})
 
-		if err == io.EOF {
-			break
+	if err == nil && !found {
+		return SnippetNotFoundError{Keyword: s.Keyword}
+	}
+
+	return err
+}
+
+// Merge adds and replaces the snippets in the snippets file at once, nothing is saved when a snippet to add
+// has the keyword of a saved snippet or one to replace is not saved. The snippets file stays encrypted if it is
+func Merge(filePath string, added, replaced []*Snippet) error {
+	rows, encrypted, err := readRows(filePath)
+
+	if os.IsNotExist(err) {
+		rows, err = nil, nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(rows))
+
+	for i, row := range rows {
+		index[fromRow(row).Keyword] = i
+	}
+
+	for _, snippet := range replaced {
+		i, ok := index[snippet.Keyword]
+
+		if !ok {
+			return SnippetNotFoundError{Keyword: snippet.Keyword}
 		}
 
-		if err != nil {
+		if rows[i], err = snippet.mergedRow(); err != nil {
 			return err
 		}
+	}
+
+	for _, snippet := range added {
+		if _, ok := index[snippet.Keyword]; ok {
+			return SnippetAlreadyExistError{Keyword: snippet.Keyword}
+		}
 
-		keyword, content, description := row[0], row[1], row[2]
+		row, err := snippet.mergedRow()
 
-		if s.Keyword == keyword {
-			continue
+		if err != nil {
+			return err
 		}
 
-		rows = append(rows, []string{keyword, content, description})
+		index[snippet.Keyword] = len(rows)
+		rows = append(rows, row)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	return writeRows(filePath, rows, encrypted)
+}
+
+// mergedRow returns the row of the merged snippet, updated now unless it tells when it was
+func (s *Snippet) mergedRow() ([]string, error) {
+	if s.Updated.IsZero() {
+		s.Updated = now()
 	}
 
-	err = file.Close()
+	return s.row()
+}
+
+// rewrite the snippets file with the rows returned by the edit function, nil removes the row.
+// It tells if any row was changed. The snippets file stays encrypted if it is
+func rewrite(filePath string, edit func(row []string) []string) (bool, error) {
+	rows := make([][]string, 0)
+	changed := false
+
+	read, encrypted, err := readRows(filePath)
 
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	file, err = os.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, FileMode)
+	for _, row := range read {
+		edited := edit(row)
 
-	defer util.Check(file.Close)
+		if edited == nil || strings.Join(edited, "\x00") != strings.Join(row, "\x00") {
+			changed = true
+		}
 
-	w := csv.NewWriter(file)
+		if edited != nil {
+			rows = append(rows, edited)
+		}
+	}
+
+	return changed, writeRows(filePath, rows, encrypted)
+}
+
+// Parse reads the snippets of a library, e.g. a snippets file shared by a colleague
+func Parse(r io.Reader) ([]*Snippet, error) {
+	var snippets []*Snippet
+
+	csvr := newReader(r)
+
+	for {
+		row, err := csvr.Read()
 
-	return w.WriteAll(rows)
+		if err == io.EOF {
+			return snippets, nil
+		}
+
+		if err != nil {
+			return snippets, err
+		}
+
+		snippets = append(snippets, fromRow(row))
+	}
 }
 
 // Get all saved snippets
@@ -133,125 +292,289 @@ func Search(searchTerm, filePath string) ([]*Snippet, error) {
 	return searchSnippets(searchTerm, filePath, SEARCH_FUZZY)
 }
 
+// Placeholder represents a {name} placeholder in the snippet content.
+// Placeholders written as {name:secret} are marked as secret
+type Placeholder struct {
+	Name   string
+	Secret bool
+}
+
+// Placeholders returns the unique placeholders of the snippet in order of appearance
+func (s *Snippet) Placeholders() []Placeholder {
+	var placeholders []Placeholder
+	seen := make(map[string]int)
+
+	for _, match := range placeholderIndexes(s.Content) {
+		name := s.Content[match[2]:match[3]]
+		secret := match[4] != -1
+
+		if i, ok := seen[name]; ok {
+			placeholders[i].Secret = placeholders[i].Secret || secret
+			continue
+		}
+
+		seen[name] = len(placeholders)
+		placeholders = append(placeholders, Placeholder{Name: name, Secret: secret})
+	}
+
+	return placeholders
+}
+
+// placeholderIndexes returns the positions of the placeholders in the content and of their submatches
+func placeholderIndexes(content string) [][]int {
+	var indexes [][]int
+
+	for _, match := range placeholderRegexp.FindAllStringSubmatchIndex(content, -1) {
+		// ${VAR} is a shell variable, not a placeholder
+		if match[0] > 0 && content[match[0]-1] == '$' {
+			continue
+		}
+
+		indexes = append(indexes, match)
+	}
+
+	return indexes
+}
+
 // Build snippet actual content using the given placeholders
 func (s *Snippet) Build(placeholders map[string]string) string {
 	content := s.Content
 
 	for k, v := range placeholders {
 		content = strings.Replace(content, fmt.Sprintf("{%s}", k), v, -1)
+		content = strings.Replace(content, fmt.Sprintf("{%s:secret}", k), v, -1)
 	}
 
 	return content
 }
 
-// SnippetDir returns the default directory path to the saved snippets
-func SnippetDir() (string, error) {
-	currentUser, err := user.Current()
+// Fill sets the placeholders of the snippet which are not in the mapper to the given values, e.g. of a profile
+func (s *Snippet) Fill(mapper map[string]string, values map[string]string) {
+	for _, placeholder := range s.Placeholders() {
+		if _, ok := mapper[placeholder.Name]; ok {
+			continue
+		}
 
-	if err != nil {
-		return "", err
+		if value, ok := values[placeholder.Name]; ok {
+			mapper[placeholder.Name] = value
+		}
 	}
-
-	dir := path.Join(currentUser.HomeDir, ".snip")
-
-	return dir, nil
 }
 
-// SnippetFile returns the default file path to the saved snippets
-func SnippetFile() (string, error) {
-	dir, err := SnippetDir()
+// ExpandMatrix returns every combination of the given placeholder values.
+// Combinations are ordered by placeholder name, the last name varying fastest
+func ExpandMatrix(values map[string][]string) []map[string]string {
+	names := make([]string, 0, len(values))
 
-	if err != nil {
-		return "", err
+	for name := range values {
+		names = append(names, name)
 	}
 
-	filePath := path.Join(dir, "snippets.csv")
-
-	return filePath, err
-}
+	sort.Strings(names)
 
-func init() {
-	dir, err := SnippetDir()
+	combinations := []map[string]string{{}}
 
-	panicIfError(err)
+	for _, name := range names {
+		if len(values[name]) == 0 {
+			continue
+		}
 
-	if _, err = os.Stat(dir); os.IsNotExist(err) {
-		err = os.MkdirAll(dir, 0755)
-		panicIfError(err)
-	}
+		expanded := make([]map[string]string, 0, len(combinations)*len(values[name]))
 
-	filePath, err := SnippetFile()
+		for _, combination := range combinations {
+			for _, value := range values[name] {
+				next := make(map[string]string, len(combination)+1)
 
-	panicIfError(err)
+				for k, v := range combination {
+					next[k] = v
+				}
 
-	_, err = os.Stat(filePath)
+				next[name] = value
+				expanded = append(expanded, next)
+			}
+		}
 
-	if os.IsNotExist(err) {
-		file, err := os.OpenFile(filePath, os.O_RDONLY|os.O_CREATE, FileMode)
-		panicIfError(err)
-		defer util.Check(file.Close)
+		combinations = expanded
 	}
+
+	return combinations
 }
 
 func searchSnippets(searchTerm, filePath string, exact SearchCode) ([]*Snippet, error) {
 	var snippets []*Snippet
 
-	file, err := os.Open(filePath)
-	defer util.Check(file.Close)
+	data, _, err := load(filePath)
+
+	if os.IsNotExist(err) {
+		return snippets, nil
+	}
 
 	if err != nil {
 		return snippets, err
 	}
 
-	matcher := fuzzy.MatchFold
+	matcher := matcherOf(exact)
 
-	if exact == SEARCH_EXACT {
-		matcher = exactMatcher
-	} else if exact == SEARCH_MATCH_ANY {
-		matcher = func(k, c string) bool { return true }
-	}
+	csvr := newReader(bytes.NewReader(data))
 
-	csvr := csv.NewReader(file)
+	// fallback is the first snippet matching the exact search by its description or content, when no keyword matches
+	var fallback *Snippet
 
 	for {
 		row, err := csvr.Read()
 
 		if err == io.EOF {
-			return snippets, nil
+			break
 		}
 
 		if err != nil {
 			return snippets, err
 		}
 
-		keyword, content, description := row[0], row[1], row[2]
+		found := fromRow(row)
+
+		// the keyword is looked up first and only the returned snippet is decrypted,
+		// the encrypted content is not matched by an exact search
+		if exact == SEARCH_EXACT {
+			if matcher(searchTerm, found.Keyword) {
+				fallback = found
+				break
+			}
+
+			if fallback == nil && (matcher(searchTerm, found.Description) || !found.Encrypted && matcher(searchTerm, found.Content)) {
+				fallback = found
+			}
 
-		if !matcher(searchTerm, keyword) && !matcher(searchTerm, content) && !matcher(searchTerm, description) {
 			continue
 		}
 
-		found := &Snippet{
-			Keyword:     keyword,
-			Content:     content,
-			Description: description,
+		// the content of the encrypted snippets is decrypted only to be matched or returned
+		if !matcher(searchTerm, found.Keyword) && !matcher(searchTerm, found.Description) {
+			if err := found.decrypt(); err != nil {
+				return snippets, err
+			}
+
+			if !matcher(searchTerm, found.Content) {
+				continue
+			}
+		}
+
+		if err := found.decrypt(); err != nil {
+			return snippets, err
 		}
 
 		snippets = append(snippets, found)
+	}
 
-		if exact == SEARCH_EXACT {
-			return snippets, nil
+	if fallback == nil {
+		return snippets, nil
+	}
+
+	if err := fallback.decrypt(); err != nil {
+		return snippets, err
+	}
+
+	return append(snippets, fallback), nil
+}
+
+// ParseTags reads comma separated tags, e.g. "k8s, db"
+func ParseTags(tags string) []string {
+	var parsed []string
+
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			parsed = append(parsed, tag)
+		}
+	}
+
+	return parsed
+}
+
+// HasTag tells if the snippet is tagged with the given tag
+func (s *Snippet) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
 		}
 	}
 
-	return snippets, nil
+	return false
+}
+
+// newReader returns a CSV reader accepting rows saved before new columns were added
+func newReader(r io.Reader) *csv.Reader {
+	csvr := csv.NewReader(r)
+	csvr.FieldsPerRecord = -1
+	return csvr
+}
+
+// toRow returns the CSV row of the snippet: keyword, content, description, type, sensitive, tags, updated
+func (s *Snippet) toRow() []string {
+	sensitive, updated := "", ""
+
+	if s.Sensitive {
+		sensitive = "sensitive"
+	}
+
+	if !s.Updated.IsZero() {
+		updated = s.Updated.Format(time.RFC3339)
+	}
+
+	return []string{s.Keyword, s.Content, s.Description, string(s.Type), sensitive, strings.Join(s.Tags, ","), updated}
+}
+
+// fromRow reads a snippet from a CSV row, the columns after the description are optional
+func fromRow(row []string) *Snippet {
+	column := func(i int) string {
+		if i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	updated, _ := time.Parse(time.RFC3339, column(6))
+
+	return &Snippet{
+		Keyword:     column(0),
+		Content:     column(1),
+		Description: column(2),
+		Type:        SnippetType(column(3)),
+		Sensitive:   column(4) == "sensitive",
+		Tags:        ParseTags(column(5)),
+		Encrypted:   strings.HasPrefix(column(1), ENCRYPTED_PREFIX),
+		Updated:     updated,
+	}
+}
+
+// now is the time recorded when saving a snippet, to the second like in the snippets file
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// matches tells if the search term matches the keyword, the content or the description
+func (s *Snippet) matches(searchTerm string, mode SearchCode) bool {
+	matcher := matcherOf(mode)
+	return matcher(searchTerm, s.Keyword) || matcher(searchTerm, s.Content) || matcher(searchTerm, s.Description)
+}
+
+// matcherOf returns how the search term is matched against a field, fuzzy by default
+func matcherOf(mode SearchCode) func(source, target string) bool {
+	switch mode {
+	case SEARCH_EXACT:
+		return exactMatcher
+	case SEARCH_MATCH_ANY:
+		return func(k, c string) bool { return true }
+	case SEARCH_SUBSTRING:
+		return substringMatcher
+	}
+
+	return fuzzy.MatchFold
 }
 
 func exactMatcher(source string, target string) bool {
 	return strings.TrimSpace(source) == strings.TrimSpace(target)
 }
 
-func panicIfError(e error) {
-	if e != nil {
-		panic(e)
-	}
+func substringMatcher(source string, target string) bool {
+	return strings.Contains(strings.ToLower(target), strings.ToLower(strings.TrimSpace(source)))
 }
@@ -2,6 +2,7 @@ package snippet_test
 
 import (
 	"fmt"
+	"github.com/baopham/snip/crypt"
 	. "github.com/baopham/snip/snippet"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 )
 
 type SnippetContent string
@@ -33,7 +35,7 @@ var _ = Describe("Snippet", func() {
 		snippetCounter = 1
 	)
 
-	saveSnippet := func(snippet Snippet, filePath string) {
+	saveSnippet := func(snippet *Snippet, filePath string) {
 		err := snippet.Save(filePath)
 		Expect(err).To(BeNil())
 	}
@@ -87,7 +89,7 @@ var _ = Describe("Snippet", func() {
 
 			snippet := seedSnippet()
 
-			saveSnippet(snippet, fakeFilePath)
+			saveSnippet(&snippet, fakeFilePath)
 
 			content := getFileContent(fakeFilePath)
 
@@ -99,11 +101,11 @@ var _ = Describe("Snippet", func() {
 		It("should save the snippets", func() {
 			snippet1 := seedSnippet()
 
-			saveSnippet(snippet1, fakeFilePath)
+			saveSnippet(&snippet1, fakeFilePath)
 
 			snippet2 := seedSnippet()
 
-			saveSnippet(snippet2, fakeFilePath)
+			saveSnippet(&snippet2, fakeFilePath)
 
 			content := strings.Split(string(getFileContent(fakeFilePath)), "\n")
 
@@ -116,7 +118,7 @@ var _ = Describe("Snippet", func() {
 		It("should save the snippet correctly", func() {
 			snippet := seedSnippet()
 
-			saveSnippet(snippet, fakeFilePath)
+			saveSnippet(&snippet, fakeFilePath)
 
 			content := getFileContent(fakeFilePath)
 
@@ -128,7 +130,7 @@ var _ = Describe("Snippet", func() {
 		It("should return the found Snippet", func() {
 			snippet := seedSnippet()
 
-			saveSnippet(snippet, fakeFilePath)
+			saveSnippet(&snippet, fakeFilePath)
 
 			found, err := SearchExact(snippet.Keyword, fakeFilePath)
 
@@ -146,15 +148,15 @@ var _ = Describe("Snippet", func() {
 				Description: "Find processes using a certain port",
 				Content:     "lsof -i :{p}",
 			}
-			saveSnippet(snippet1, fakeFilePath)
+			saveSnippet(&snippet1, fakeFilePath)
 			snippet2 = Snippet{
 				Keyword:     "port2",
 				Description: "Find processes using a certain port",
 				Content:     "lsof -i :{p}",
 			}
-			saveSnippet(snippet2, fakeFilePath)
+			saveSnippet(&snippet2, fakeFilePath)
 			snippet3 = seedSnippet()
-			saveSnippet(snippet3, fakeFilePath)
+			saveSnippet(&snippet3, fakeFilePath)
 		})
 
 		assertSearchResult := func(searchTerm string) {
@@ -181,7 +183,7 @@ var _ = Describe("Snippet", func() {
 		It("should not save it again", func() {
 			snippet := seedSnippet()
 
-			saveSnippet(snippet, fakeFilePath)
+			saveSnippet(&snippet, fakeFilePath)
 
 			err := snippet.Save(fakeFilePath)
 
@@ -191,6 +193,82 @@ var _ = Describe("Snippet", func() {
 		})
 	})
 
+	Context("when calling snippet.Update()", func() {
+		It("should replace the saved snippet with the same keyword", func() {
+			snippet := seedSnippet()
+			saveSnippet(&snippet, fakeFilePath)
+
+			updated := Snippet{Keyword: snippet.Keyword, Content: "new content", Updated: snippet.Updated.Add(time.Hour)}
+
+			Expect(updated.Update(fakeFilePath)).To(BeNil())
+
+			found, err := SearchExact(snippet.Keyword, fakeFilePath)
+
+			Expect(err).To(BeNil())
+			Expect(*found).To(Equal(updated))
+		})
+
+		It("should return SnippetNotFoundError when the snippet does not exist", func() {
+			saveSnippet(&Snippet{Keyword: "port", Content: "lsof -i :{p}"}, fakeFilePath)
+
+			err := (&Snippet{Keyword: "missing"}).Update(fakeFilePath)
+
+			Expect(err).To(MatchError(SnippetNotFoundError{Keyword: "missing"}))
+		})
+	})
+
+	Context("when calling snippet.Match()", func() {
+		It("should return the values of the placeholders", func() {
+			snippet := Snippet{Keyword: "port", Content: "lsof -i :{p} -u {user} # {p}"}
+
+			values, ok := snippet.Match("lsof  -i :8000 -u bao # 8000")
+
+			Expect(ok).To(BeTrue())
+			Expect(values).To(Equal(map[string]string{"p": "8000", "user": "bao"}))
+
+			_, ok = snippet.Match("lsof -i :8000 -u bao # 8001")
+
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should match the shell variables literally", func() {
+			snippet := Snippet{Keyword: "home", Content: "ls ${HOME}/{dir}"}
+
+			values, ok := snippet.Match("ls ${HOME}/src")
+
+			Expect(ok).To(BeTrue())
+			Expect(values).To(Equal(map[string]string{"dir": "src"}))
+
+			_, ok = snippet.Match("ls /root/src")
+
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("when calling snippet.Literal()", func() {
+		It("should count the content without the placeholders", func() {
+			snippet := Snippet{Keyword: "port", Content: "lsof -i :{p} # {p} {token:secret} ${HOME}"}
+
+			Expect(snippet.Literal()).To(Equal(len("lsof -i : #   ${HOME}")))
+		})
+	})
+
+	Context("when calling Parse()", func() {
+		It("should read the snippets of the library", func() {
+			snippets, err := Parse(strings.NewReader("port,lsof -i :{p},Find processes,,,net,2020-01-02T03:04:05Z\n"))
+
+			Expect(err).To(BeNil())
+			Expect(snippets).To(HaveLen(1))
+			Expect(*snippets[0]).To(Equal(Snippet{
+				Keyword:     "port",
+				Content:     "lsof -i :{p}",
+				Description: "Find processes",
+				Tags:        []string{"net"},
+				Updated:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			}))
+		})
+	})
+
 	Context("when calling snippet.Build()", func() {
 		It("should build the snippet using the given placeholders", func() {
 			By("replacing one placeholder")
@@ -225,21 +303,248 @@ var _ = Describe("Snippet", func() {
 		})
 	})
 
+	Context("when calling snippet.Placeholders()", func() {
+		It("should return the unique placeholders in order of appearance", func() {
+			snippet := Snippet{
+				Keyword: "login",
+				Content: "curl -u {user}:{token:secret} {host}/{path} && echo ${HOME} {user}",
+			}
+
+			Expect(snippet.Placeholders()).To(Equal([]Placeholder{
+				{Name: "user"},
+				{Name: "token", Secret: true},
+				{Name: "host"},
+				{Name: "path"},
+			}))
+
+			By("replacing the secret placeholders when building")
+
+			content := snippet.Build(map[string]string{
+				"user":  "bao",
+				"token": "t0k3n",
+				"host":  "localhost",
+				"path":  "api",
+			})
+
+			Expect(content).To(Equal("curl -u bao:t0k3n localhost/api && echo ${HOME} bao"))
+		})
+	})
+
+	Context("when calling ExpandMatrix()", func() {
+		It("should return every combination of the placeholder values", func() {
+			combinations := ExpandMatrix(map[string][]string{
+				"p":    {"8000", "8001"},
+				"host": {"a", "b"},
+				"user": {"bao"},
+			})
+
+			Expect(combinations).To(Equal([]map[string]string{
+				{"host": "a", "p": "8000", "user": "bao"},
+				{"host": "a", "p": "8001", "user": "bao"},
+				{"host": "b", "p": "8000", "user": "bao"},
+				{"host": "b", "p": "8001", "user": "bao"},
+			}))
+		})
+
+		It("should return one empty combination without placeholders", func() {
+			Expect(ExpandMatrix(nil)).To(Equal([]map[string]string{{}}))
+		})
+	})
+
+	Context("when calling snippet.Fill()", func() {
+		It("should only fill the placeholders which were not given", func() {
+			snippet := Snippet{Keyword: "psql", Content: "psql -h {host} -U {user} {db}"}
+			mapper := map[string]string{"user": "bao"}
+
+			snippet.Fill(mapper, map[string]string{"host": "db.prod", "user": "admin", "ns": "web"})
+
+			Expect(mapper).To(Equal(map[string]string{"host": "db.prod", "user": "bao"}))
+			Expect(snippet.Build(mapper)).To(Equal("psql -h db.prod -U bao {db}"))
+		})
+	})
+
+	Context("when reading snippets saved before the type column", func() {
+		It("should read them alongside the new snippets", func() {
+			err := ioutil.WriteFile(fakeFilePath, []byte("port,lsof -i :{p},Find processes\n"), FileMode)
+			Expect(err).To(BeNil())
+
+			runbook := Snippet{
+				Keyword: "deploy",
+				Content: "# check the branch\ngit status",
+				Type:    TYPE_RUNBOOK,
+			}
+
+			saveSnippet(&runbook, fakeFilePath)
+
+			snippets, err := GetAll(fakeFilePath)
+
+			Expect(err).To(BeNil())
+			Expect(snippets).To(HaveLen(2))
+			Expect(*snippets[0]).To(Equal(Snippet{Keyword: "port", Content: "lsof -i :{p}", Description: "Find processes"}))
+			Expect(*snippets[1]).To(Equal(runbook))
+		})
+	})
+
+	Context("when saving a sensitive snippet", func() {
+		It("should read it back as sensitive", func() {
+			snippet := seedSnippet()
+			snippet.Sensitive = true
+
+			saveSnippet(&snippet, fakeFilePath)
+
+			found, err := SearchExact(snippet.Keyword, fakeFilePath)
+
+			Expect(err).To(BeNil())
+			Expect(*found).To(Equal(snippet))
+		})
+	})
+
+	Context("when saving a snippet with tags", func() {
+		It("should read it back with its tags", func() {
+			snippet := seedSnippet()
+			snippet.Tags = []string{"aws", "prod"}
+
+			saveSnippet(&snippet, fakeFilePath)
+
+			found, err := SearchExact(snippet.Keyword, fakeFilePath)
+
+			Expect(err).To(BeNil())
+			Expect(*found).To(Equal(snippet))
+			Expect(found.HasTag("AWS")).To(BeTrue())
+		})
+	})
+
+	Context("when calling snippet.Steps()", func() {
+		It("should return the notes and commands of the runbook", func() {
+			runbook := Snippet{
+				Keyword: "deploy",
+				Type:    TYPE_RUNBOOK,
+				Content: "# Make sure nobody is deploying\n\ngit pull origin {branch}\nmake build \\\n  ENV={env}\n# Done",
+			}
+
+			Expect(runbook.Steps()).To(Equal([]Step{
+				{Note: true, Content: "Make sure nobody is deploying"},
+				{Content: "git pull origin {branch}"},
+				{Content: "make build \\\nENV={env}"},
+				{Note: true, Content: "Done"},
+			}))
+
+			By("giving each step its own placeholders")
+
+			Expect(runbook.Steps()[2].Snippet(&runbook).Placeholders()).To(Equal([]Placeholder{{Name: "env"}}))
+
+			By("keeping the runbook encrypted and sensitive")
+
+			runbook.Encrypted, runbook.Sensitive = true, true
+			step := runbook.Steps()[1].Snippet(&runbook)
+
+			Expect(step.Encrypted).To(BeTrue())
+			Expect(step.Sensitive).To(BeTrue())
+		})
+	})
+
+	Context("when calling snippet.Expand()", func() {
+		It("should replace the includes recursively and merge their placeholders", func() {
+			saveSnippet(&Snippet{Keyword: "kctx", Content: "kubectl --context {ctx} {@kns}"}, fakeFilePath)
+			saveSnippet(&Snippet{Keyword: "kns", Content: "-n {ns}"}, fakeFilePath)
+
+			snippet := Snippet{Keyword: "pods", Content: "{@kctx} get pods {pod}"}
+
+			expanded, err := snippet.Expand(NewLibrary(fakeFilePath))
+
+			Expect(err).To(BeNil())
+			Expect(expanded.Content).To(Equal("kubectl --context {ctx} -n {ns} get pods {pod}"))
+			Expect(expanded.Placeholders()).To(Equal([]Placeholder{{Name: "ctx"}, {Name: "ns"}, {Name: "pod"}}))
+
+			By("leaving the snippet untouched")
+
+			Expect(snippet.Content).To(Equal("{@kctx} get pods {pod}"))
+		})
+
+		It("should be sensitive when an included snippet is", func() {
+			saveSnippet(&Snippet{Keyword: "login", Content: "login {@token}"}, fakeFilePath)
+			saveSnippet(&Snippet{Keyword: "token", Content: "--token s3cr3t", Sensitive: true}, fakeFilePath)
+
+			snippet := Snippet{Keyword: "deploy", Content: "{@login} && deploy"}
+
+			expanded, err := snippet.Expand(NewLibrary(fakeFilePath))
+
+			Expect(err).To(BeNil())
+			Expect(expanded.Content).To(Equal("login --token s3cr3t && deploy"))
+			Expect(expanded.Sensitive).To(BeTrue())
+			Expect(snippet.Sensitive).To(BeFalse())
+		})
+
+		It("should return MissingIncludeError when the included snippet does not exist", func() {
+			saveSnippet(&Snippet{Keyword: "kctx", Content: "kubectl {@missing}"}, fakeFilePath)
+
+			snippet := Snippet{Keyword: "pods", Content: "{@kctx} get pods"}
+
+			_, err := snippet.Expand(NewLibrary(fakeFilePath))
+
+			Expect(err).To(MatchError(MissingIncludeError{Keyword: "kctx", Include: "missing"}))
+		})
+
+		It("should return IncludeCycleError when the snippets include each other", func() {
+			saveSnippet(&Snippet{Keyword: "a", Content: "a {@b}"}, fakeFilePath)
+			saveSnippet(&Snippet{Keyword: "b", Content: "b {@a}"}, fakeFilePath)
+
+			snippet, err := SearchExact("a", fakeFilePath)
+			Expect(err).To(BeNil())
+
+			_, err = snippet.Expand(NewLibrary(fakeFilePath))
+
+			Expect(err).To(MatchError(IncludeCycleError{Keywords: []string{"a", "b", "a"}}))
+		})
+	})
+
+	Context("when calling Merge()", func() {
+		It("should add and replace the snippets at once", func() {
+			saveSnippet(&Snippet{Keyword: "port", Content: "lsof -i :{p}", Description: "build"}, fakeFilePath)
+
+			Expect(Merge(fakeFilePath,
+				[]*Snippet{{Keyword: "build", Content: "make build"}},
+				[]*Snippet{{Keyword: "port", Content: "lsof -i :{p} -P"}},
+			)).To(BeNil())
+
+			snippets, err := GetAll(fakeFilePath)
+			Expect(err).To(BeNil())
+			Expect(snippets).To(HaveLen(2))
+			Expect(snippets[0].Content).To(Equal("lsof -i :{p} -P"))
+			Expect(snippets[1].Keyword).To(Equal("build"))
+		})
+
+		It("should save nothing when a keyword is already saved", func() {
+			saveSnippet(&Snippet{Keyword: "port", Content: "lsof -i :{p}"}, fakeFilePath)
+
+			err := Merge(fakeFilePath,
+				[]*Snippet{{Keyword: "build", Content: "make build"}, {Keyword: "port", Content: "ss -lnt"}},
+				nil,
+			)
+
+			Expect(err).To(MatchError(SnippetAlreadyExistError{Keyword: "port"}))
+
+			snippets, err := GetAll(fakeFilePath)
+			Expect(err).To(BeNil())
+			Expect(snippets).To(HaveLen(1))
+		})
+	})
+
 	Context("when calling snippet.Remove()", func() {
 		saveThreeSnippets := func() (Snippet, Snippet, Snippet) {
 			By("saving 3 snippets")
 
 			snippet1 := seedSnippet()
 
-			saveSnippet(snippet1, fakeFilePath)
+			saveSnippet(&snippet1, fakeFilePath)
 
 			snippet2 := seedSnippet()
 
-			saveSnippet(snippet2, fakeFilePath)
+			saveSnippet(&snippet2, fakeFilePath)
 
 			snippet3 := seedSnippet()
 
-			saveSnippet(snippet3, fakeFilePath)
+			saveSnippet(&snippet3, fakeFilePath)
 
 			content := getFileContent(fakeFilePath)
 
@@ -315,3 +620,172 @@ var _ = Describe("Snippet", func() {
 		})
 	})
 })
+
+var _ = Describe("Encryption", func() {
+	var dir, file string
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = ioutil.TempDir("", "snip-encryption")
+		Expect(err).To(BeNil())
+
+		file = path.Join(dir, "snippets.csv")
+		Passphrase = func(check func(string) error) (string, error) {
+			if check != nil {
+				return "passphrase", check("passphrase")
+			}
+
+			return "passphrase", nil
+		}
+	})
+
+	AfterEach(func() {
+		Passphrase = nil
+		os.RemoveAll(dir)
+	})
+
+	It("should find the data already encrypted", func() {
+		plain := path.Join(dir, "plain.csv")
+
+		Expect((&Snippet{Keyword: "port", Content: "lsof -i :{p}"}).Save(plain)).To(BeNil())
+
+		data, err := EncryptedData(plain, path.Join(dir, "missing.csv"))
+		Expect(err).To(BeNil())
+		Expect(data).To(BeNil())
+
+		Expect((&Snippet{Keyword: "login", Content: "aws sso login --profile prod", Encrypted: true}).Save(file)).To(BeNil())
+
+		data, err = EncryptedData(plain, file)
+		Expect(err).To(BeNil())
+
+		content, err := crypt.Decrypt(data, "passphrase")
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(Equal("aws sso login --profile prod"))
+
+		Expect(EncryptFile(plain)).To(BeNil())
+
+		data, err = EncryptedData(plain, file)
+		Expect(err).To(BeNil())
+
+		content, err = crypt.Decrypt(data, "passphrase")
+		Expect(err).To(BeNil())
+		Expect(string(content)).To(ContainSubstring("lsof -i :{p}"))
+	})
+
+	It("should decrypt only the snippet found by keyword", func() {
+		Expect((&Snippet{Keyword: "login", Content: "aws sso login --profile prod", Encrypted: true}).Save(file)).To(BeNil())
+		Expect((&Snippet{Keyword: "port", Content: "lsof -i :{p}"}).Save(file)).To(BeNil())
+
+		decrypted := 0
+		Passphrase = func(check func(string) error) (string, error) {
+			decrypted++
+			return "passphrase", check("passphrase")
+		}
+
+		found, err := SearchExact("port", file)
+
+		Expect(err).To(BeNil())
+		Expect(found.Content).To(Equal("lsof -i :{p}"))
+		Expect(decrypted).To(Equal(0))
+
+		found, err = SearchExact("aws sso login --profile prod", file)
+
+		Expect(err).To(BeNil())
+		Expect(found).To(BeNil())
+		Expect(decrypted).To(Equal(0))
+
+		found, err = SearchExact("login", file)
+
+		Expect(err).To(BeNil())
+		Expect(found.Content).To(Equal("aws sso login --profile prod"))
+		Expect(decrypted).To(Equal(1))
+	})
+
+	It("should expand an encrypted include into an encrypted snippet", func() {
+		Expect((&Snippet{Keyword: "login", Content: "aws sso login --profile prod", Encrypted: true}).Save(file)).To(BeNil())
+
+		snippet := Snippet{Keyword: "deploy", Content: "{@login} && make deploy"}
+
+		expanded, err := snippet.Expand(NewLibrary(file))
+
+		Expect(err).To(BeNil())
+		Expect(expanded.Content).To(Equal("aws sso login --profile prod && make deploy"))
+		Expect(expanded.Encrypted).To(BeTrue())
+	})
+
+	It("should encrypt the content of an encrypted snippet only", func() {
+		Expect((&Snippet{Keyword: "login", Content: "aws sso login --profile prod", Encrypted: true}).Save(file)).To(BeNil())
+		Expect((&Snippet{Keyword: "port", Content: "lsof -i :{p}"}).Save(file)).To(BeNil())
+
+		b, err := ioutil.ReadFile(file)
+		Expect(err).To(BeNil())
+		Expect(string(b)).To(ContainSubstring("login," + ENCRYPTED_PREFIX))
+		Expect(string(b)).NotTo(ContainSubstring("aws sso"))
+		Expect(string(b)).To(ContainSubstring("lsof -i :{p}"))
+
+		found, err := SearchExact("login", file)
+
+		Expect(err).To(BeNil())
+		Expect(found.Encrypted).To(BeTrue())
+		Expect(found.Content).To(Equal("aws sso login --profile prod"))
+
+		found.Encrypted = false
+		Expect(found.Update(file)).To(BeNil())
+
+		b, _ = ioutil.ReadFile(file)
+		Expect(string(b)).To(ContainSubstring("aws sso login --profile prod"))
+	})
+
+	It("should search in the content of the encrypted snippets", func() {
+		Expect((&Snippet{Keyword: "login", Content: "aws sso login", Encrypted: true}).Save(file)).To(BeNil())
+
+		found, err := Search("sso", file)
+
+		Expect(err).To(BeNil())
+		Expect(found).To(HaveLen(1))
+		Expect(found[0].Content).To(Equal("aws sso login"))
+	})
+
+	It("should keep the encrypted snippets file encrypted", func() {
+		Expect((&Snippet{Keyword: "port", Content: "lsof -i :{p}"}).Save(file)).To(BeNil())
+		Expect(EncryptFile(file)).To(BeNil())
+		Expect(EncryptFile(file)).To(Equal(AlreadyEncryptedError{Name: file}))
+
+		Expect((&Snippet{Keyword: "pods", Content: "kubectl get pods"}).Save(file)).To(BeNil())
+		Expect((&Snippet{Keyword: "port"}).Remove(file)).To(BeNil())
+
+		encrypted, err := IsEncryptedFile(file)
+		Expect(err).To(BeNil())
+		Expect(encrypted).To(BeTrue())
+
+		b, _ := ioutil.ReadFile(file)
+		Expect(string(b)).NotTo(ContainSubstring("kubectl"))
+
+		snippets, err := GetAll(file)
+
+		Expect(err).To(BeNil())
+		Expect(snippets).To(HaveLen(1))
+		Expect(snippets[0].Content).To(Equal("kubectl get pods"))
+
+		Expect(DecryptFile(file)).To(BeNil())
+		Expect(DecryptFile(file)).To(Equal(NotEncryptedError{Name: file}))
+
+		b, _ = ioutil.ReadFile(file)
+		Expect(string(b)).To(ContainSubstring("kubectl get pods"))
+	})
+
+	It("should refuse a wrong passphrase", func() {
+		Expect((&Snippet{Keyword: "port", Content: "lsof -i :{p}"}).Save(file)).To(BeNil())
+		Expect(EncryptFile(file)).To(BeNil())
+
+		Passphrase = func(check func(string) error) (string, error) {
+			return "wrong", check("wrong")
+		}
+
+		_, err := GetAll(file)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.(DecryptError).Name).To(Equal(file))
+	})
+})
@@ -0,0 +1,17 @@
+package suggest
+
+type InvalidNameError struct {
+	Name string
+}
+
+func (e InvalidNameError) Error() string {
+	return "Invalid placeholder name " + e.Name + ", use letters, digits, _, . or -"
+}
+
+type TakenNameError struct {
+	Name string
+}
+
+func (e TakenNameError) Error() string {
+	return "The placeholder {" + e.Name + "} already exists, use another name"
+}
@@ -0,0 +1,117 @@
+package suggest
+
+import (
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// zshExtendedRegexp matches the timestamp of the zsh extended history, e.g. ": 1600000000:0;"
+var zshExtendedRegexp = regexp.MustCompile(`^: \d+:\d+;`)
+
+// bashTimestampRegexp matches the timestamp lines bash writes with HISTTIMEFORMAT
+var bashTimestampRegexp = regexp.MustCompile(`^#\d+$`)
+
+// HistoryFiles returns the history files of bash, zsh and fish which exist
+func HistoryFiles() []string {
+	usr, err := user.Current()
+
+	if err != nil {
+		return nil
+	}
+
+	home := usr.HomeDir
+
+	candidates := []string{
+		path.Join(home, ".bash_history"),
+		path.Join(home, ".zsh_history"),
+		path.Join(home, ".local", "share", "fish", "fish_history"),
+	}
+
+	if histFile := os.Getenv("HISTFILE"); histFile != "" {
+		candidates = append([]string{histFile}, candidates...)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil && !seen[candidate] {
+			seen[candidate] = true
+			files = append(files, candidate)
+		}
+	}
+
+	return files
+}
+
+// ReadHistory reads the commands of a bash, zsh (plain or extended) or fish history file
+func ReadHistory(filePath string) ([]string, error) {
+	b, err := ioutil.ReadFile(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseHistory(string(b)), nil
+}
+
+// ParseHistory reads the commands of a history, its format is detected from its lines
+func ParseHistory(content string) []string {
+	lines := strings.Split(strings.Replace(content, "\r\n", "\n", -1), "\n")
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "- cmd: ") {
+			return parseFish(lines)
+		}
+	}
+
+	var (
+		commands []string
+		current  []string
+	)
+
+	for _, line := range lines {
+		if len(current) == 0 {
+			if bashTimestampRegexp.MatchString(line) {
+				continue
+			}
+
+			line = zshExtendedRegexp.ReplaceAllString(line, "")
+		}
+
+		// zsh saves the multiline commands with a backslash at the end of the lines
+		if strings.HasSuffix(line, "\\") {
+			current = append(current, strings.TrimSuffix(line, "\\"))
+			continue
+		}
+
+		current = append(current, line)
+		command := strings.TrimSpace(strings.Join(current, "\n"))
+		current = nil
+
+		if command != "" {
+			commands = append(commands, command)
+		}
+	}
+
+	return commands
+}
+
+// parseFish reads the fish history: "- cmd: the command" followed by the "when" and "paths" of the command
+func parseFish(lines []string) []string {
+	var commands []string
+
+	unescape := strings.NewReplacer(`\\`, `\`, `\n`, "\n")
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "- cmd: ") {
+			commands = append(commands, strings.TrimSpace(unescape.Replace(strings.TrimPrefix(line, "- cmd: "))))
+		}
+	}
+
+	return commands
+}
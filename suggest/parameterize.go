@@ -0,0 +1,228 @@
+package suggest
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/baopham/snip/snippet"
+)
+
+const (
+	KIND_UUID   = "uuid"
+	KIND_STRING = "string"
+	KIND_IP     = "ip"
+	KIND_HOST   = "host"
+	KIND_PORT   = "port"
+	KIND_PATH   = "path"
+	KIND_NUMBER = "number"
+)
+
+// Parameter is a literal value of a command likely to vary, e.g. the port in lsof -i :9000
+type Parameter struct {
+	Value string
+	Kind  string
+	// Name proposed for the placeholder
+	Name string
+	// Positions are the start and end offsets of each occurrence of the value
+	Positions [][2]int
+}
+
+type detector struct {
+	kind string
+	// re matches the value in its first group
+	re *regexp.Regexp
+	// name is the placeholder name when no option precedes the value
+	name string
+}
+
+// nameRegexp matches the names a placeholder can have
+var nameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// fileExtensions are not mistaken for top level domains
+var fileExtensions = map[string]bool{
+	"txt": true, "log": true, "json": true, "yaml": true, "yml": true, "sh": true, "py": true, "go": true,
+	"md": true, "csv": true, "conf": true, "tar": true, "gz": true, "zip": true, "js": true, "ts": true,
+	"html": true, "sql": true, "xml": true, "toml": true, "ini": true, "env": true, "lock": true, "pem": true,
+}
+
+// detectors are tried in order, a value is only detected once
+var detectors = []detector{
+	{KIND_UUID, regexp.MustCompile(`\b([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})\b`), "id"},
+	{KIND_STRING, regexp.MustCompile(`"([^"{}$]+)"|'([^'{}]+)'`), "text"},
+	{KIND_IP, regexp.MustCompile(`\b((?:\d{1,3}\.){3}\d{1,3})\b`), "ip"},
+	{KIND_HOST, regexp.MustCompile(`(?:^|[\s@/])((?:[A-Za-z0-9-]+\.)+[A-Za-z]{2,6})\b`), "host"},
+	{KIND_PORT, regexp.MustCompile(`:(\d{2,5})\b`), "port"},
+	{KIND_PATH, regexp.MustCompile(`(?:^|[\s=])((?:~|\.{1,2})?/[^\s'"|;&<>]+)`), "path"},
+	{KIND_NUMBER, regexp.MustCompile(`(?:^|[\s=])(\d+)\b`), "n"},
+}
+
+// Parameters detects the ports, IPs, hosts, paths, UUIDs, numbers and quoted strings of the content
+// and proposes a placeholder name for each, after the option before the value if any, e.g. -p 80 gives {p}
+func Parameters(content string) []*Parameter {
+	var taken [][2]int
+
+	// the existing placeholders are left as is
+	for _, match := range regexp.MustCompile(`\{[^{}\s]+\}`).FindAllStringIndex(content, -1) {
+		taken = append(taken, [2]int{match[0], match[1]})
+	}
+
+	overlaps := func(start, end int) bool {
+		for _, span := range taken {
+			if start < span[1] && end > span[0] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	byValue := make(map[string]*Parameter)
+	var parameters []*Parameter
+
+	for _, d := range detectors {
+		for _, match := range d.re.FindAllStringSubmatchIndex(content, -1) {
+			start, end := match[2], match[3]
+
+			if start < 0 {
+				start, end = match[4], match[5]
+			}
+
+			value := content[start:end]
+
+			if overlaps(start, end) || !standalone(d.kind, value, content[end:]) {
+				continue
+			}
+
+			taken = append(taken, [2]int{start, end})
+
+			if p, ok := byValue[value]; ok {
+				p.Positions = append(p.Positions, [2]int{start, end})
+				continue
+			}
+
+			p := &Parameter{Value: value, Kind: d.kind, Positions: [][2]int{{start, end}}}
+			p.Name = optionBefore(content, start)
+
+			if p.Name == "" {
+				p.Name = d.name
+			}
+
+			byValue[value] = p
+			parameters = append(parameters, p)
+		}
+	}
+
+	sort.SliceStable(parameters, func(i, j int) bool {
+		return parameters[i].Positions[0][0] < parameters[j].Positions[0][0]
+	})
+
+	names := placeholderNames(content)
+
+	for _, p := range parameters {
+		p.Name = placeholderName(p.Name, names)
+	}
+
+	return parameters
+}
+
+// Rename names the placeholder of the parameter, the name must be valid and not the one of
+// another placeholder of the content or of the other parameters
+func Rename(content string, parameters []*Parameter, parameter *Parameter, name string) error {
+	if !nameRegexp.MatchString(name) {
+		return InvalidNameError{Name: name}
+	}
+
+	taken := placeholderNames(content)
+
+	for _, p := range parameters {
+		if p != parameter {
+			taken[p.Name] = true
+		}
+	}
+
+	if taken[name] {
+		return TakenNameError{Name: name}
+	}
+
+	parameter.Name = name
+
+	return nil
+}
+
+// placeholderNames returns the names of the placeholders already in the content
+func placeholderNames(content string) map[string]bool {
+	names := make(map[string]bool)
+
+	for _, placeholder := range (&snippet.Snippet{Content: content}).Placeholders() {
+		names[placeholder.Name] = true
+	}
+
+	return names
+}
+
+// Parameterize replaces the values of the parameters with their placeholders
+func Parameterize(content string, parameters []*Parameter) string {
+	type replacement struct {
+		start, end int
+		name       string
+	}
+
+	var replacements []replacement
+
+	for _, p := range parameters {
+		for _, position := range p.Positions {
+			replacements = append(replacements, replacement{position[0], position[1], p.Name})
+		}
+	}
+
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start > replacements[j].start
+	})
+
+	for _, r := range replacements {
+		content = content[:r.start] + "{" + r.name + "}" + content[r.end:]
+	}
+
+	return content
+}
+
+// optionBefore returns the name of the option the value belongs to, e.g. port for --port 80 or --port=80
+func optionBefore(content string, start int) string {
+	// the value of a quoted string starts after the quote
+	before := strings.TrimRight(content[:start], `"'`)
+
+	if strings.HasSuffix(before, "=") {
+		before = strings.TrimSuffix(before, "=")
+	} else if strings.HasSuffix(before, " ") {
+		before = strings.TrimRight(before, " ")
+	} else {
+		return ""
+	}
+
+	fields := strings.Fields(before)
+
+	if len(fields) == 0 {
+		return ""
+	}
+
+	option := fields[len(fields)-1]
+
+	if !strings.HasPrefix(option, "-") || strings.Trim(option, "-") == "" {
+		return ""
+	}
+
+	return strings.TrimLeft(option, "-")
+}
+
+// standalone tells if the value is the whole parameter, e.g. not the 1 of 1.5 or the file name config.yaml taken for a host
+func standalone(kind, value, after string) bool {
+	switch kind {
+	case KIND_HOST:
+		return !fileExtensions[strings.ToLower(value[strings.LastIndex(value, ".")+1:])]
+	case KIND_NUMBER:
+		return after == "" || strings.ContainsAny(after[:1], " \t\n;|&)")
+	}
+
+	return true
+}
@@ -0,0 +1,251 @@
+package suggest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/baopham/snip/snippet"
+)
+
+// Suggestion is a snippet made out of similar commands of the history
+type Suggestion struct {
+	Content string
+	// Count is how many times the commands were run
+	Count int
+	// Examples are the distinct commands of the suggestion, most frequent first
+	Examples []string
+}
+
+// Options of the suggestions
+type Options struct {
+	// MinCount is how many times a command has to be run to be suggested
+	MinCount int
+	// LongCommand is the length from which a command is suggested even if it was run once
+	LongCommand int
+}
+
+// cluster is a group of commands with the same tokens except a few ones
+type cluster struct {
+	commands []string
+	tokens   [][]string
+	counts   []int
+}
+
+func (c *cluster) count() int {
+	total := 0
+
+	for _, count := range c.counts {
+		total += count
+	}
+
+	return total
+}
+
+// Suggest clusters the commands differing only by a few arguments, the arguments
+// which vary become placeholders. Commands an existing snippet could generate are skipped
+func Suggest(commands []string, existing []*snippet.Snippet, options Options) []*Suggestion {
+	counts := make(map[string]int)
+	var distinct []string
+
+	for _, command := range commands {
+		if ignored(command) {
+			continue
+		}
+
+		if counts[command] == 0 {
+			distinct = append(distinct, command)
+		}
+
+		counts[command]++
+	}
+
+	var clusters []*cluster
+
+	for _, command := range distinct {
+		if saved(command, existing) {
+			continue
+		}
+
+		tokens := strings.Fields(command)
+		joined := false
+
+		for _, c := range clusters {
+			if similar(c.tokens[0], tokens) {
+				c.commands = append(c.commands, command)
+				c.tokens = append(c.tokens, tokens)
+				c.counts = append(c.counts, counts[command])
+				joined = true
+				break
+			}
+		}
+
+		if !joined {
+			clusters = append(clusters, &cluster{commands: []string{command}, tokens: [][]string{tokens}, counts: []int{counts[command]}})
+		}
+	}
+
+	var suggestions []*Suggestion
+
+	for _, c := range clusters {
+		// a command run alone is kept as typed, with its quotes and line breaks
+		content := c.commands[0]
+
+		if len(c.commands) > 1 {
+			content = template(c.tokens)
+		}
+
+		if c.count() < options.MinCount && len(content) < options.LongCommand {
+			continue
+		}
+
+		suggestions = append(suggestions, &Suggestion{Content: content, Count: c.count(), Examples: examples(c)})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Count > suggestions[j].Count
+	})
+
+	return suggestions
+}
+
+// ignored commands are too short to be worth a snippet
+func ignored(command string) bool {
+	tokens := strings.Fields(command)
+
+	return len(tokens) < 2 || len(command) < 10 || tokens[0] == "snip" || tokens[0] == "cd"
+}
+
+func saved(command string, existing []*snippet.Snippet) bool {
+	for _, s := range existing {
+		if _, ok := s.Match(command); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// similar commands have the same program and number of tokens, and differ by at most
+// 2 tokens which are less than half of them
+func similar(a, b []string) bool {
+	if len(a) != len(b) || a[0] != b[0] || len(a) < 2 {
+		return false
+	}
+
+	different := 0
+
+	for i := range a {
+		if a[i] != b[i] {
+			different++
+		}
+	}
+
+	return different <= 2 && different*2 < len(a)
+}
+
+// template keeps the tokens shared by the commands and replaces the others with placeholders,
+// named after the option before them, e.g. -n {n}, or {p1}, {p2}...
+func template(commands [][]string) string {
+	first := commands[0]
+	tokens := make([]string, len(first))
+	names := make(map[string]bool)
+
+	for i, token := range first {
+		tokens[i] = token
+
+		if !varies(commands, i) {
+			continue
+		}
+
+		prefix := sharedPrefix(commands, i)
+		name := ""
+
+		if i > 0 && strings.HasPrefix(first[i-1], "-") {
+			name = strings.TrimLeft(first[i-1], "-")
+		} else if prefix != "" {
+			name = strings.TrimLeft(strings.TrimRight(prefix, "=:"), "-")
+		}
+
+		name = placeholderName(name, names)
+		tokens[i] = prefix + "{" + name + "}"
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+func varies(commands [][]string, i int) bool {
+	for _, tokens := range commands[1:] {
+		if tokens[i] != commands[0][i] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sharedPrefix returns the prefix of an option shared by the varying tokens, e.g. --port= in --port=8000
+func sharedPrefix(commands [][]string, i int) string {
+	token := commands[0][i]
+	end := strings.IndexAny(token, "=:")
+
+	if end < 0 {
+		return ""
+	}
+
+	prefix := token[:end+1]
+
+	for _, tokens := range commands[1:] {
+		if !strings.HasPrefix(tokens[i], prefix) {
+			return ""
+		}
+	}
+
+	return prefix
+}
+
+// placeholderName makes the name unique by adding a number to it,
+// the placeholders without name are numbered: {p1}, {p2}...
+func placeholderName(name string, taken map[string]bool) string {
+	valid := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+		return -1
+	}, name)
+
+	candidate := valid
+
+	if valid == "" {
+		valid, candidate = "p", "p1"
+	}
+
+	for i := 2; taken[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", valid, i)
+	}
+
+	taken[candidate] = true
+
+	return candidate
+}
+
+func examples(c *cluster) []string {
+	order := make([]int, len(c.tokens))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return c.counts[order[i]] > c.counts[order[j]]
+	})
+
+	examples := make([]string, len(order))
+
+	for i, index := range order {
+		examples[i] = c.commands[index]
+	}
+
+	return examples
+}
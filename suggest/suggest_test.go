@@ -0,0 +1,131 @@
+package suggest_test
+
+import (
+	"github.com/baopham/snip/snippet"
+	. "github.com/baopham/snip/suggest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Suggest", func() {
+	Context("when calling ParseHistory()", func() {
+		It("should read the bash history", func() {
+			Expect(ParseHistory("#1600000000\nls -la\n\ngit status\n")).To(Equal([]string{"ls -la", "git status"}))
+		})
+
+		It("should read the zsh extended history with multiline commands", func() {
+			history := ": 1600000000:0;git status\n: 1600000001:2;for f in *; do\\\necho $f\\\ndone\n"
+
+			Expect(ParseHistory(history)).To(Equal([]string{"git status", "for f in *; do\necho $f\ndone"}))
+		})
+
+		It("should read the fish history", func() {
+			history := "- cmd: git status\n  when: 1600000000\n- cmd: echo a\\nb\n  when: 1600000001\n  paths:\n    - b\n"
+
+			Expect(ParseHistory(history)).To(Equal([]string{"git status", "echo a\nb"}))
+		})
+	})
+
+	Context("when calling Suggest()", func() {
+		commands := []string{
+			"kubectl logs -n web api-1",
+			"kubectl logs -n web api-2",
+			"kubectl logs -n jobs worker-1",
+			"lsof -i :8000",
+			"lsof -i :8000",
+			"lsof -i :8001",
+			"git status --short",
+			"docker run --rm -it -v $PWD:/src -w /src golang:1.21 go test ./...",
+		}
+
+		It("should turn the varying arguments into placeholders", func() {
+			suggestions := Suggest(commands, nil, Options{MinCount: 3, LongCommand: 60})
+
+			Expect(suggestions).To(HaveLen(3))
+			Expect(suggestions[0]).To(Equal(&Suggestion{
+				Content:  "kubectl logs -n {n} {p1}",
+				Count:    3,
+				Examples: []string{"kubectl logs -n web api-1", "kubectl logs -n web api-2", "kubectl logs -n jobs worker-1"},
+			}))
+			Expect(suggestions[1].Content).To(Equal("lsof -i :{i}"))
+			Expect(suggestions[1].Examples).To(Equal([]string{"lsof -i :8000", "lsof -i :8001"}))
+			Expect(suggestions[2].Content).To(Equal("docker run --rm -it -v $PWD:/src -w /src golang:1.21 go test ./..."))
+		})
+
+		It("should skip the commands of the saved snippets", func() {
+			existing := []*snippet.Snippet{{Keyword: "port", Content: "lsof -i :{p}"}}
+
+			suggestions := Suggest(commands, existing, Options{MinCount: 3, LongCommand: 60})
+
+			Expect(suggestions).To(HaveLen(2))
+			Expect(suggestions[0].Content).To(Equal("kubectl logs -n {n} {p1}"))
+		})
+	})
+
+	Context("when calling Parameters()", func() {
+		It("should detect the values likely to vary and propose names", func() {
+			content := `curl -H "Authorization: x" https://api.example.com:8443/v1/users/0b8f6a1e-3c1d-4f7a-9b2e-5d6c7e8f9a0b --retry 3 -o ./out.json && ping 10.0.0.1`
+
+			parameters := Parameters(content)
+
+			values := make([]string, len(parameters))
+			names := make([]string, len(parameters))
+
+			for i, p := range parameters {
+				values[i], names[i] = p.Value, p.Name
+			}
+
+			Expect(values).To(Equal([]string{"Authorization: x", "api.example.com", "8443", "0b8f6a1e-3c1d-4f7a-9b2e-5d6c7e8f9a0b", "3", "./out.json", "10.0.0.1"}))
+			Expect(names).To(Equal([]string{"H", "host", "port", "id", "retry", "o", "ip"}))
+		})
+
+		It("should not detect file names as hosts or the existing placeholders", func() {
+			parameters := Parameters("cat config.yaml {p} 1.5")
+
+			Expect(parameters).To(BeEmpty())
+		})
+
+		It("should not propose the names of the existing placeholders", func() {
+			parameters := Parameters("lsof -i :{port} && lsof -i :9000")
+
+			Expect(parameters).To(HaveLen(1))
+			Expect(parameters[0].Name).To(Equal("port2"))
+		})
+	})
+
+	Context("when calling Rename()", func() {
+		content := "ssh -p 2222 {user}@10.0.0.1"
+
+		It("should rename the placeholder of the parameter", func() {
+			parameters := Parameters(content)
+
+			Expect(Rename(content, parameters, parameters[0], "ssh.port")).To(BeNil())
+			Expect(parameters[0].Name).To(Equal("ssh.port"))
+		})
+
+		It("should reject the invalid names", func() {
+			parameters := Parameters(content)
+
+			Expect(Rename(content, parameters, parameters[0], "the port")).To(Equal(InvalidNameError{Name: "the port"}))
+			Expect(Rename(content, parameters, parameters[0], "port:secret")).To(Equal(InvalidNameError{Name: "port:secret"}))
+			Expect(parameters[0].Name).To(Equal("p"))
+		})
+
+		It("should reject the names of the other placeholders", func() {
+			parameters := Parameters(content)
+
+			Expect(Rename(content, parameters, parameters[0], "user")).To(Equal(TakenNameError{Name: "user"}))
+			Expect(Rename(content, parameters, parameters[0], "ip")).To(Equal(TakenNameError{Name: "ip"}))
+			Expect(Rename(content, parameters, parameters[0], "p")).To(BeNil())
+		})
+	})
+
+	Context("when calling Parameterize()", func() {
+		It("should replace every occurrence of the values", func() {
+			content := "lsof -i :9000 && echo 9000 on /tmp/a"
+			parameters := Parameters(content)
+
+			Expect(Parameterize(content, parameters)).To(Equal("lsof -i :{port} && echo {port} on {path}"))
+		})
+	})
+})
@@ -0,0 +1,12 @@
+package util
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// IsTerminal tells if the file is a terminal, e.g. os.Stdout is not when piped
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}